@@ -0,0 +1,325 @@
+package arccache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/list"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+// Cache is an adaptive replacement cache. Entries seen once live in T1 and
+// entries seen at least twice live in T2. Keys of entries evicted from T1
+// and T2 are remembered in the ghost lists B1 and B2. A hit in B1 means T1
+// was too small and grows the target size p of T1, a hit in B2 shrinks it.
+// This way the cache balances recency and frequency, so a scan only churns
+// T1 while frequently used entries stay in T2.
+type Cache[K comparable, V any] struct {
+	// data holds both resident and ghost entries.
+	data     map[K]*entry[K, V]
+	t1       *list.List[*entry[K, V]]
+	t2       *list.List[*entry[K, V]]
+	b1       *list.List[*entry[K, V]]
+	b2       *list.List[*entry[K, V]]
+	expiry   *lifecycle.ExpiryQueue[*entry[K, V]]
+	p        int
+	capacity int
+	ttl      time.Duration
+	janitor  *janitor.Janitor
+	stats    *lifecycle.Recorder[K, V]
+	mu       sync.Mutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.Capacity <= 0 {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	cache := Cache[K, V]{
+		data:     make(map[K]*entry[K, V], 2*params.Capacity),
+		t1:       list.New[*entry[K, V]](),
+		t2:       list.New[*entry[K, V]](),
+		b1:       list.New[*entry[K, V]](),
+		b2:       list.New[*entry[K, V]](),
+		expiry:   lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		capacity: params.Capacity,
+		ttl:      params.TTL,
+		stats:    lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok || !c.isResident(v) {
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	c.moveToFrequent(v)
+	c.stats.Hit()
+	return v.value, true
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok || !c.isResident(v) {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+// Len does not count remembered keys of evicted entries.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.residentLen()
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, l := range []*list.List[*entry[K, V]]{c.t1, c.t2} {
+		for e := l.Front(); e != nil; e = e.Next() {
+			c.stats.Removal(e.Value.key, e.Value.value, removal.Deleted)
+		}
+	}
+
+	clear(c.data)
+	c.t1.Clear()
+	c.t2.Clear()
+	c.b1.Clear()
+	c.b2.Clear()
+	c.expiry.Clear()
+	c.p = 0
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats.Snapshot(c.residentLen())
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && c.isResident(v) && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	switch {
+	case !ok:
+		c.addNewEntry(key, value, expiredAt)
+	case c.isResident(v):
+		c.updateEntry(v, value, expiredAt)
+	default:
+		c.restoreGhost(v, value, expiredAt)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	c.moveToFrequent(entry)
+}
+
+// restoreGhost stores the value of a key remembered in B1 or B2 and adapts
+// the target size of T1 to the ghost hit.
+func (c *Cache[K, V]) restoreGhost(entry *entry[K, V], value V, expiredAt time.Time) {
+	inB2 := c.b2.Contains(entry.element)
+	if inB2 {
+		c.p = max(0, c.p-max(c.b1.Len()/c.b2.Len(), 1))
+		c.b2.Remove(entry.element)
+	} else {
+		c.p = min(c.capacity, c.p+max(c.b2.Len()/c.b1.Len(), 1))
+		c.b1.Remove(entry.element)
+	}
+
+	if c.residentLen() >= c.capacity {
+		c.replace(inB2)
+	}
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	entry.element = c.t2.PushBack(entry)
+	entry.expiry = c.expiry.Push(entry)
+}
+
+func (c *Cache[K, V]) addNewEntry(key K, value V, expiredAt time.Time) {
+	recentLen := c.t1.Len() + c.b1.Len()
+	totalLen := recentLen + c.t2.Len() + c.b2.Len()
+
+	switch {
+	case recentLen >= c.capacity && c.t1.Len() >= c.capacity:
+		// T1 takes the whole cache, its oldest entry is evicted without
+		// being remembered.
+		c.removeEntry(c.t1.Front().Value, removal.Capacity)
+	case recentLen >= c.capacity:
+		c.removeGhost(c.b1.Front().Value)
+	case totalLen >= 2*c.capacity:
+		c.removeGhost(c.b2.Front().Value)
+	}
+
+	if c.residentLen() >= c.capacity {
+		c.replace(false)
+	}
+
+	entry := newEntry(key, value, expiredAt)
+	entry.element = c.t1.PushBack(entry)
+	entry.expiry = c.expiry.Push(entry)
+	c.data[key] = entry
+}
+
+// replace evicts the oldest entry of T1 or T2 depending on the target size
+// of T1 and remembers its key in the matching ghost list.
+func (c *Cache[K, V]) replace(inB2 bool) {
+	t1Len := c.t1.Len()
+	if t1Len > 0 && (t1Len > c.p || (inB2 && t1Len == c.p) || c.t2.Len() == 0) {
+		c.evictToGhost(c.t1.Front().Value, c.b1)
+		return
+	}
+
+	if c.t2.Len() > 0 {
+		c.evictToGhost(c.t2.Front().Value, c.b2)
+	}
+}
+
+func (c *Cache[K, V]) evictToGhost(entry *entry[K, V], ghosts *list.List[*entry[K, V]]) {
+	c.t1.Remove(entry.element)
+	c.t2.Remove(entry.element)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, removal.Capacity)
+
+	entry.value = c.getZeroValue()
+	entry.element = ghosts.PushBack(entry)
+}
+
+// moveToFrequent makes the entry the youngest one in T2.
+func (c *Cache[K, V]) moveToFrequent(entry *entry[K, V]) {
+	if c.t2.Contains(entry.element) {
+		c.t2.MoveToBack(entry.element)
+		return
+	}
+
+	c.t1.Remove(entry.element)
+	entry.element = c.t2.PushBack(entry)
+}
+
+func (c *Cache[K, V]) isResident(entry *entry[K, V]) bool {
+	return c.t1.Contains(entry.element) || c.t2.Contains(entry.element)
+}
+
+func (c *Cache[K, V]) residentLen() int {
+	return c.t1.Len() + c.t2.Len()
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.t1.Remove(entry.element)
+	c.t2.Remove(entry.element)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) removeGhost(entry *entry[K, V]) {
+	delete(c.data, entry.key)
+	c.b1.Remove(entry.element)
+	c.b2.Remove(entry.element)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
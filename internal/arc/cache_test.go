@@ -0,0 +1,156 @@
+package arccache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/list"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestCache_ValueWasReadTwice_ValueWasMovedToT2() {
+	cache := s.newCache(4)
+
+	err := cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"key1"}, listKeys(cache.t1))
+
+	_, exists := cache.Get("key1")
+	require.True(s.T(), exists)
+	assert.Empty(s.T(), listKeys(cache.t1))
+	assert.Equal(s.T(), []string{"key1"}, listKeys(cache.t2))
+}
+
+func (s *CacheSuite) TestCache_Scan_FrequentValuesSurvived() {
+	cache := s.newCache(10)
+
+	for i := range 5 {
+		key := fmt.Sprintf("hot%d", i)
+		err := cache.Set(key, i)
+		require.NoError(s.T(), err)
+		cache.Get(key)
+	}
+
+	for i := range 100 {
+		err := cache.Set(fmt.Sprintf("scan%d", i), i)
+		require.NoError(s.T(), err)
+	}
+
+	for i := range 5 {
+		value, exists := cache.Get(fmt.Sprintf("hot%d", i))
+		assert.True(s.T(), exists)
+		assert.Equal(s.T(), i, value)
+	}
+	assert.Equal(s.T(), 10, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_GhostHitInB1_TargetOfT1Grew() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	cache.Get("key2")
+	require.NoError(s.T(), cache.Set("key3", 3))
+	assert.Equal(s.T(), []string{"key1"}, listKeys(cache.b1))
+
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists, "ghost entry should not be returned")
+
+	require.NoError(s.T(), cache.Set("key1", 10))
+	assert.Equal(s.T(), 1, cache.p)
+
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 10, value)
+	assert.Contains(s.T(), listKeys(cache.t2), "key1")
+}
+
+func (s *CacheSuite) TestCache_GhostHitInB2_TargetOfT1Shrank() {
+	cache := s.newCache(2)
+	cache.p = 2
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	cache.Get("key1")
+	require.NoError(s.T(), cache.Set("key2", 2))
+	cache.Get("key2")
+	require.NoError(s.T(), cache.Set("key3", 3))
+	assert.Equal(s.T(), []string{"key1"}, listKeys(cache.b2))
+
+	require.NoError(s.T(), cache.Set("key1", 10))
+	assert.Equal(s.T(), 1, cache.p)
+	assert.Contains(s.T(), listKeys(cache.t2), "key1")
+}
+
+func (s *CacheSuite) TestCache_ManyEvictions_GhostsWereBounded() {
+	cache := s.newCache(10)
+
+	for i := range 1000 {
+		key := fmt.Sprintf("key%d", i%50)
+		err := cache.Set(key, i)
+		require.NoError(s.T(), err)
+		if i%3 == 0 {
+			cache.Get(key)
+		}
+
+		require.LessOrEqual(s.T(), cache.residentLen(), 10)
+		require.LessOrEqual(s.T(), cache.t1.Len()+cache.b1.Len(), 10)
+		require.LessOrEqual(s.T(), len(cache.data), 20)
+		require.Equal(s.T(), len(cache.data), cache.t1.Len()+cache.t2.Len()+cache.b1.Len()+cache.b2.Len())
+	}
+}
+
+func (s *CacheSuite) TestCache_DeleteGhostKey_ReturnFalse() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	cache.Get("key2")
+	require.NoError(s.T(), cache.Set("key3", 3))
+	require.Equal(s.T(), []string{"key1"}, listKeys(cache.b1))
+
+	assert.False(s.T(), cache.Delete("key1"))
+	assert.Equal(s.T(), 2, cache.Len())
+}
+
+func (s *CacheSuite) newCache(capacity int) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+func listKeys(l *list.List[*entry[string, int]]) []string {
+	var keys []string
+	for e := l.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.key)
+	}
+
+	return keys
+}
@@ -0,0 +1,22 @@
+package arccache
+
+import (
+	"testing"
+
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestConformanceSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &cachetest.Suite{
+		NewCache: func(params cachetest.Params) (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{
+				Capacity:        params.Capacity,
+				TTL:             params.TTL,
+				CleanupInterval: params.CleanupInterval,
+				OnEvict:         params.OnEvict,
+			})
+		},
+	})
+}
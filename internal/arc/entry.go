@@ -0,0 +1,30 @@
+package arccache
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/list"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+	// element links the entry into one of T1, T2, B1 or B2.
+	element *list.Element[*entry[K, V]]
+	// expiry links a resident entry into the expiry queue.
+	expiry *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiredAt: expiredAt,
+	}
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
@@ -0,0 +1,15 @@
+package arccache
+
+import (
+	"errors"
+
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+)
+
+var (
+	ErrIllegalCapacity        = errors.New("capacity should be greater than 0")
+	ErrIllegalTTL             = errors.New("ttl should not be negative")
+	ErrIllegalCleanupInterval = errors.New("cleanup interval should not be negative")
+	ErrIllegalEntryTTL        = lifecycle.ErrIllegalEntryTTL
+	ErrIllegalDeadline        = lifecycle.ErrIllegalDeadline
+)
@@ -0,0 +1,21 @@
+package cacheerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCostCapacityRequired is returned when an entry with a cost is stored in
+// a cache limited by max bytes only, where the cost would limit nothing.
+var ErrCostCapacityRequired = errors.New("capacity is required to store entries with cost")
+
+// CostTooLargeError is returned when an entry costs more than the whole
+// cache capacity and therefore can never be stored.
+type CostTooLargeError struct {
+	Cost     int64
+	Capacity int64
+}
+
+func (e *CostTooLargeError) Error() string {
+	return fmt.Sprintf("entry cost %d exceeds cache capacity %d", e.Cost, e.Capacity)
+}
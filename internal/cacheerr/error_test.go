@@ -0,0 +1,26 @@
+package cacheerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostTooLargeError(t *testing.T) {
+	t.Run("Error message", func(t *testing.T) {
+		err := &CostTooLargeError{Cost: 10, Capacity: 5}
+		assert.Equal(t, "entry cost 10 exceeds cache capacity 5", err.Error())
+	})
+
+	t.Run("Error can be found in chain", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", &CostTooLargeError{Cost: 10, Capacity: 5})
+
+		var costErr *CostTooLargeError
+		require.True(t, errors.As(err, &costErr))
+		assert.Equal(t, int64(10), costErr.Cost)
+		assert.Equal(t, int64(5), costErr.Capacity)
+	})
+}
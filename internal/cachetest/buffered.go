@@ -0,0 +1,105 @@
+package cachetest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// BufferedReadsSuite is run by every cache package supporting buffered
+// reads. NewCache has to return a cache with buffered reads enabled.
+type BufferedReadsSuite struct {
+	suite.Suite
+	NewCache func(capacity int) (Cache, error)
+}
+
+func (s *BufferedReadsSuite) newCache(capacity int) Cache {
+	cache, err := s.NewCache(capacity)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+func (s *BufferedReadsSuite) TestBufferedReadsOfRemovedValue_AccessWasSkipped() {
+	cache := s.newCache(2)
+
+	err := cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+	_, exists := cache.Get("key1")
+	require.True(s.T(), exists)
+	require.True(s.T(), cache.Delete("key1"))
+
+	err = cache.Set("key2", 2)
+	require.NoError(s.T(), err)
+	err = cache.Set("key3", 3)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+}
+
+func (s *BufferedReadsSuite) TestBufferedReadsConcurrently_StatsWereCollected() {
+	cache := s.newCache(100)
+
+	for i := range 100 {
+		err := cache.Set(fmt.Sprintf("key%d", i), i)
+		require.NoError(s.T(), err)
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				key := fmt.Sprintf("key%d", i%200)
+				cache.Get(key)
+				if i%10 == 0 {
+					_ = cache.Set(key, i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := cache.Stats()
+	assert.Equal(s.T(), uint64(8000), stats.Hits+stats.Misses)
+	assert.Equal(s.T(), 100, cache.Len())
+}
+
+// BenchmarkBufferedReads compares the default and the buffered read path on
+// a skewed trace. Besides time it reports the hit ratio, which shows how
+// much the policy loses to dropped reads, and the share of dropped reads.
+// newCache returns a cache with buffered reads enabled or not, dropped
+// returns the number of reads the cache has dropped.
+func BenchmarkBufferedReads(b *testing.B, newCache func(capacity int, buffered bool) (Cache, error), dropped func(cache Cache) uint64) {
+	const capacity = 1_000
+
+	trace := ZipfTrace(1<<16, 100_001, 1)
+
+	for _, buffered := range []bool{false, true} {
+		for _, goroutines := range []int{1, 8, 64} {
+			b.Run(fmt.Sprintf("buffered=%t/goroutines=%d", buffered, goroutines), func(b *testing.B) {
+				cache, err := newCache(capacity, buffered)
+				require.NoError(b, err)
+
+				b.ResetTimer()
+				RunParallel(b, goroutines, func(i int) {
+					key := trace[i&(len(trace)-1)]
+					if _, ok := cache.Get(key); !ok {
+						_ = cache.Set(key, i)
+					}
+				})
+				b.StopTimer()
+
+				b.ReportMetric(cache.Stats().HitRatio(), "hit-ratio")
+				if buffered {
+					b.ReportMetric(float64(dropped(cache))/float64(b.N), "dropped/op")
+				}
+			})
+		}
+	}
+}
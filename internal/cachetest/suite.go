@@ -0,0 +1,329 @@
+// Package cachetest provides a conformance test suite which every eviction
+// policy has to pass, whatever entries it prefers to evict.
+package cachetest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// Cache is the behaviour shared by the caches under test.
+type Cache interface {
+	Get(key string) (int, bool)
+	Set(key string, value int) error
+	SetWithTTL(key string, value int, ttl time.Duration) error
+	SetWithDeadline(key string, value int, deadline time.Time) error
+	Delete(key string) bool
+	Len() int
+	Clear()
+	Stats() stats.Stats
+	ResetStats()
+	Close()
+}
+
+type Params struct {
+	Capacity        int
+	TTL             time.Duration
+	CleanupInterval time.Duration
+	OnEvict         func(key string, value int, reason removal.Reason)
+}
+
+// Suite is run by every cache package with its own NewCache.
+type Suite struct {
+	suite.Suite
+	NewCache func(params Params) (Cache, error)
+}
+
+func (s *Suite) newCache(params Params) Cache {
+	if params.TTL == 0 {
+		params.TTL = time.Hour
+	}
+
+	cache, err := s.NewCache(params)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+	s.T().Cleanup(cache.Close)
+
+	return cache
+}
+
+func (s *Suite) TestSetAndGet_ValueWasReturned() {
+	cache := s.newCache(Params{Capacity: 10})
+
+	err := cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 1, value)
+
+	value, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+	assert.Zero(s.T(), value)
+}
+
+func (s *Suite) TestSetExistingKey_ValueWasReplaced() {
+	recorder := &Recorder{}
+	cache := s.newCache(Params{Capacity: 10, OnEvict: recorder.Record})
+
+	err := cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+	err = cache.Set("key1", 2)
+	require.NoError(s.T(), err)
+
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 2, value)
+	assert.Equal(s.T(), 1, cache.Len())
+	assert.Equal(s.T(), []Removal{{Key: "key1", Value: 1, Reason: removal.Replaced}}, recorder.Removals())
+}
+
+func (s *Suite) TestDelete_ValueWasDeleted() {
+	recorder := &Recorder{}
+	cache := s.newCache(Params{Capacity: 10, OnEvict: recorder.Record})
+
+	err := cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+
+	assert.True(s.T(), cache.Delete("key1"))
+	assert.False(s.T(), cache.Delete("key1"))
+	assert.False(s.T(), cache.Delete("key2"))
+
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists)
+	assert.Zero(s.T(), cache.Len())
+	assert.Equal(s.T(), []Removal{{Key: "key1", Value: 1, Reason: removal.Deleted}}, recorder.Removals())
+}
+
+func (s *Suite) TestSetOverCapacity_LenDidNotExceedCapacity() {
+	recorder := &Recorder{}
+	cache := s.newCache(Params{Capacity: 10, OnEvict: recorder.Record})
+
+	for i := range 100 {
+		err := cache.Set(fmt.Sprintf("key%d", i), i)
+		require.NoError(s.T(), err)
+		require.LessOrEqual(s.T(), cache.Len(), 10)
+
+		_, exists := cache.Get(fmt.Sprintf("key%d", i))
+		assert.True(s.T(), exists, "the latest value should be stored")
+	}
+
+	assert.Equal(s.T(), 10, cache.Len())
+	assert.Equal(s.T(), uint64(90), cache.Stats().Evictions)
+
+	removals := recorder.Removals()
+	assert.Len(s.T(), removals, 90)
+	for _, r := range removals {
+		assert.Equal(s.T(), removal.Capacity, r.Reason)
+	}
+}
+
+func (s *Suite) TestSetOverCapacity_EvictedValuesAreNotReturned() {
+	recorder := &Recorder{}
+	cache := s.newCache(Params{Capacity: 10, OnEvict: recorder.Record})
+
+	for i := range 30 {
+		err := cache.Set(fmt.Sprintf("key%d", i%20), i)
+		require.NoError(s.T(), err)
+		cache.Get(fmt.Sprintf("key%d", i%7))
+	}
+
+	for _, r := range recorder.Removals() {
+		if r.Reason != removal.Capacity {
+			continue
+		}
+
+		value, exists := cache.Get(r.Key)
+		if exists {
+			assert.NotEqual(s.T(), r.Value, value, "evicted value of %s was returned", r.Key)
+		}
+	}
+}
+
+func (s *Suite) TestExpiredValue_ValueWasNotReturned() {
+	recorder := &Recorder{}
+	cache := s.newCache(Params{Capacity: 10, TTL: 20 * time.Millisecond, OnEvict: recorder.Record})
+
+	err := cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+	time.Sleep(40 * time.Millisecond)
+
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists)
+	assert.Zero(s.T(), cache.Len())
+	assert.Equal(s.T(), []Removal{{Key: "key1", Value: 1, Reason: removal.Expired}}, recorder.Removals())
+}
+
+func (s *Suite) TestSetWithTTL_ValueExpiredByOwnTTL() {
+	cache := s.newCache(Params{Capacity: 10, TTL: 20 * time.Millisecond})
+
+	err := cache.SetWithTTL("key1", 1, time.Hour)
+	require.NoError(s.T(), err)
+	err = cache.SetWithTTL("key2", 2, 0)
+	require.NoError(s.T(), err)
+	err = cache.SetWithTTL("key3", 3, 10*time.Millisecond)
+	require.NoError(s.T(), err)
+	time.Sleep(40 * time.Millisecond)
+
+	_, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	_, exists = cache.Get("key2")
+	assert.True(s.T(), exists, "value without expiration should be stored")
+	_, exists = cache.Get("key3")
+	assert.False(s.T(), exists)
+
+	err = cache.SetWithTTL("key4", 4, -time.Second)
+	assert.Error(s.T(), err)
+}
+
+func (s *Suite) TestSetWithDeadline_ValueExpiredAtDeadline() {
+	cache := s.newCache(Params{Capacity: 10})
+
+	err := cache.SetWithDeadline("key1", 1, time.Now().Add(20*time.Millisecond))
+	require.NoError(s.T(), err)
+	err = cache.SetWithDeadline("key2", 2, time.Time{})
+	require.NoError(s.T(), err)
+	time.Sleep(40 * time.Millisecond)
+
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists)
+	_, exists = cache.Get("key2")
+	assert.True(s.T(), exists)
+
+	err = cache.SetWithDeadline("key3", 3, time.Now().Add(-time.Second))
+	assert.Error(s.T(), err)
+}
+
+func (s *Suite) TestCleanupInterval_ExpiredValuesWereRemoved() {
+	cache := s.newCache(Params{Capacity: 10, TTL: 10 * time.Millisecond, CleanupInterval: 10 * time.Millisecond})
+
+	for i := range 5 {
+		err := cache.Set(fmt.Sprintf("key%d", i), i)
+		require.NoError(s.T(), err)
+	}
+
+	assert.Eventually(s.T(), func() bool {
+		return cache.Len() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *Suite) TestCleanupInterval_AliveValuesWereKept() {
+	cache := s.newCache(Params{Capacity: 10, TTL: 10 * time.Millisecond, CleanupInterval: 10 * time.Millisecond})
+
+	require.NoError(s.T(), cache.Set("expired", 1))
+	require.NoError(s.T(), cache.SetWithTTL("eternal", 2, 0))
+	require.NoError(s.T(), cache.Set("renewed", 3))
+	require.NoError(s.T(), cache.SetWithTTL("renewed", 3, time.Hour))
+
+	assert.Eventually(s.T(), func() bool {
+		return cache.Len() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	_, exists := cache.Get("eternal")
+	assert.True(s.T(), exists)
+	_, exists = cache.Get("renewed")
+	assert.True(s.T(), exists)
+}
+
+func (s *Suite) TestClear_AllValuesWereDeleted() {
+	recorder := &Recorder{}
+	cache := s.newCache(Params{Capacity: 10, OnEvict: recorder.Record})
+
+	for i := range 5 {
+		err := cache.Set(fmt.Sprintf("key%d", i), i)
+		require.NoError(s.T(), err)
+	}
+
+	cache.Clear()
+	assert.Zero(s.T(), cache.Len())
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists)
+	assert.Len(s.T(), recorder.Removals(), 5)
+
+	err := cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, cache.Len())
+}
+
+func (s *Suite) TestStats_CountersWereCollected() {
+	cache := s.newCache(Params{Capacity: 10})
+
+	err := cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+	err = cache.Set("key1", 2)
+	require.NoError(s.T(), err)
+	cache.Get("key1")
+	cache.Get("key2")
+	cache.Delete("key1")
+
+	expected := stats.Stats{
+		Hits:         1,
+		Misses:       1,
+		Sets:         2,
+		Deletions:    1,
+		Replacements: 1,
+	}
+	assert.Equal(s.T(), expected, cache.Stats())
+
+	cache.ResetStats()
+	assert.Equal(s.T(), stats.Stats{}, cache.Stats())
+}
+
+func (s *Suite) TestConcurrentAccess_LenDidNotExceedCapacity() {
+	cache := s.newCache(Params{Capacity: 50})
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				key := fmt.Sprintf("key%d", (g*31+i)%200)
+				if _, ok := cache.Get(key); !ok {
+					_ = cache.Set(key, i)
+				}
+				if i%50 == 0 {
+					cache.Delete(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(s.T(), cache.Len(), 50)
+	assert.Equal(s.T(), cache.Len(), cache.Stats().Size)
+}
+
+// Removal is an entry removal reported to OnEvict.
+type Removal struct {
+	Key    string
+	Value  int
+	Reason removal.Reason
+}
+
+// Recorder collects removals reported to OnEvict.
+type Recorder struct {
+	removals []Removal
+	mu       sync.Mutex
+}
+
+func (r *Recorder) Record(key string, value int, reason removal.Reason) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removals = append(r.removals, Removal{Key: key, Value: value, Reason: reason})
+}
+
+func (r *Recorder) Removals() []Removal {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Removal(nil), r.removals...)
+}
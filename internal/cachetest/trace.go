@@ -0,0 +1,132 @@
+package cachetest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Trace is a named sequence of requested keys.
+type Trace struct {
+	Name string
+	Keys []string
+}
+
+// Policy is a named constructor of the caches compared by
+// BenchmarkHitRatio.
+type Policy struct {
+	Name     string
+	NewCache func() (Cache, error)
+}
+
+// DefaultTraces returns a Zipf trace, the same trace interrupted by scans
+// and a loop a bit larger than a cache of 1 000 entries.
+func DefaultTraces() []Trace {
+	return []Trace{
+		{Name: "zipf", Keys: ZipfTrace(200_000, 50_000, 1)},
+		{Name: "scan", Keys: ScanTrace(200_000, 50_000, 1_000, 500, 1)},
+		{Name: "loop", Keys: LoopTrace(200_000, 1_200)},
+	}
+}
+
+// ZipfTrace returns n keys drawn from a Zipf distribution over the given
+// number of distinct keys, which resembles typical web workloads.
+func ZipfTrace(n int, keys int, seed uint64) []string {
+	zipf := rand.NewZipf(rand.New(rand.NewPCG(seed, seed)), 1.1, 1, uint64(keys-1))
+
+	trace := make([]string, n)
+	for i := range trace {
+		trace[i] = fmt.Sprintf("key%d", zipf.Uint64())
+	}
+
+	return trace
+}
+
+// ScanTrace returns a Zipf trace interrupted every period keys by a scan of
+// scanLen keys which are never requested again.
+func ScanTrace(n int, keys int, period int, scanLen int, seed uint64) []string {
+	zipf := ZipfTrace(n, keys, seed)
+
+	trace := make([]string, 0, n+n/period*scanLen)
+	scanned := 0
+	for i, key := range zipf {
+		trace = append(trace, key)
+		if (i+1)%period != 0 {
+			continue
+		}
+
+		for range scanLen {
+			trace = append(trace, fmt.Sprintf("scan%d", scanned))
+			scanned++
+		}
+	}
+
+	return trace
+}
+
+// LoopTrace returns n keys cycling over the given number of distinct keys.
+// It is the worst case for LRU when keys outnumber the capacity.
+func LoopTrace(n int, keys int) []string {
+	trace := make([]string, n)
+	for i := range trace {
+		trace[i] = fmt.Sprintf("key%d", i%keys)
+	}
+
+	return trace
+}
+
+// HitRatio replays the trace as a read-through workload: every missed key
+// is stored. It returns the share of hits.
+func HitRatio(cache Cache, trace []string) float64 {
+	hits := 0
+	for i, key := range trace {
+		if _, ok := cache.Get(key); ok {
+			hits++
+			continue
+		}
+
+		_ = cache.Set(key, i)
+	}
+
+	return float64(hits) / float64(len(trace))
+}
+
+// BenchmarkHitRatio replays every trace against a new cache of every policy
+// and reports the hit ratio of each.
+func BenchmarkHitRatio(b *testing.B, traces []Trace, policies []Policy) {
+	for _, trace := range traces {
+		for _, policy := range policies {
+			b.Run(fmt.Sprintf("trace=%s/policy=%s", trace.Name, policy.Name), func(b *testing.B) {
+				var hitRatio float64
+				for range b.N {
+					cache, err := policy.NewCache()
+					require.NoError(b, err)
+
+					hitRatio = HitRatio(cache, trace.Keys)
+					cache.Close()
+				}
+
+				b.ReportMetric(hitRatio, "hit-ratio")
+			})
+		}
+	}
+}
+
+// RunParallel splits b.N calls of op between exactly the given number of
+// goroutines.
+func RunParallel(b *testing.B, goroutines int, op func(i int)) {
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := g; i < b.N; i += goroutines {
+				op(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,273 @@
+package clockcache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+// Cache is a CLOCK cache, a second chance approximation of LRU. Entries
+// are kept in a fixed ring of slots and a hit only sets the referenced bit
+// of the entry, so hits share a read lock and never move entries. To
+// evict, the hand sweeps the ring, clearing referenced bits, and evicts the
+// first entry which was not referenced since the hand passed it last time.
+// The new entry takes the slot of the evicted one.
+type Cache[K comparable, V any] struct {
+	data  map[K]*entry[K, V]
+	slots []*entry[K, V]
+	// free holds the indexes of empty slots.
+	free    []int
+	hand    int
+	expiry  *lifecycle.ExpiryQueue[*entry[K, V]]
+	ttl     time.Duration
+	janitor *janitor.Janitor
+	stats   *lifecycle.Recorder[K, V]
+	mu      sync.RWMutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.Capacity <= 0 {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	cache := Cache[K, V]{
+		data:   make(map[K]*entry[K, V], params.Capacity),
+		slots:  make([]*entry[K, V], params.Capacity),
+		free:   make([]int, 0, params.Capacity),
+		expiry: lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		ttl:    params.TTL,
+		stats:  lifecycle.NewRecorder(params.OnEvict),
+	}
+	cache.resetFree()
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+// Get returns the value stored by the key. A hit only sets the referenced
+// bit of the entry, so concurrent hits share a read lock. An expired entry
+// is removed under the write lock.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	v, ok := c.data[key]
+	if ok && !lifecycle.IsExpired(v.expiredAt) {
+		v.referenced.Store(true)
+		value := v.value
+		c.mu.RUnlock()
+
+		c.stats.Hit()
+		return value, true
+	}
+	c.mu.RUnlock()
+
+	if ok {
+		c.removeExpired(key)
+	}
+
+	c.stats.Miss()
+	return c.getZeroValue(), false
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.data)
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		c.stats.Removal(v.key, v.value, removal.Deleted)
+	}
+
+	clear(c.data)
+	clear(c.slots)
+	c.resetFree()
+	c.hand = 0
+	c.expiry.Clear()
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stats.Snapshot(len(c.data))
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	if ok {
+		c.updateEntry(v, value, expiredAt)
+	} else {
+		c.addNewEntry(key, value, expiredAt)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	entry.referenced.Store(true)
+}
+
+func (c *Cache[K, V]) addNewEntry(key K, value V, expiredAt time.Time) {
+	if len(c.free) == 0 {
+		c.evict()
+	}
+
+	slot := c.free[len(c.free)-1]
+	c.free = c.free[:len(c.free)-1]
+
+	entry := newEntry(key, value, expiredAt)
+	entry.slot = slot
+	c.slots[slot] = entry
+	entry.expiry = c.expiry.Push(entry)
+	c.data[key] = entry
+}
+
+// evict moves the hand to the first entry which was not referenced since
+// the hand passed it last time and evicts it. It is only called when every
+// slot is taken.
+func (c *Cache[K, V]) evict() {
+	for c.slots[c.hand].referenced.Swap(false) {
+		c.advanceHand()
+	}
+
+	c.removeEntry(c.slots[c.hand], removal.Capacity)
+	c.advanceHand()
+}
+
+func (c *Cache[K, V]) advanceHand() {
+	c.hand++
+	if c.hand == len(c.slots) {
+		c.hand = 0
+	}
+}
+
+// resetFree marks every slot as empty. Slots are taken from the end of
+// free, so the ring is filled starting from the first slot.
+func (c *Cache[K, V]) resetFree() {
+	c.free = c.free[:0]
+	for i := len(c.slots) - 1; i >= 0; i-- {
+		c.free = append(c.free, i)
+	}
+}
+
+// removeExpired removes the entry by the key if it is still expired once
+// the write lock is taken.
+func (c *Cache[K, V]) removeExpired(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.slots[entry.slot] = nil
+	c.free = append(c.free, entry.slot)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
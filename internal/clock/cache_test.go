@@ -0,0 +1,128 @@
+package clockcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestCache_Hit_SlotWasNotChanged() {
+	cache := s.newCache(3)
+
+	for i := range 3 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+	cache.Get("key0")
+
+	assert.Equal(s.T(), []string{"key0", "key1", "key2"}, slotKeys(cache))
+	assert.True(s.T(), cache.data["key0"].referenced.Load())
+}
+
+func (s *CacheSuite) TestCache_NotEnoughCapacity_UnreferencedValueWasReplaced() {
+	cache := s.newCache(3)
+
+	for i := range 3 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+	cache.Get("key0")
+
+	require.NoError(s.T(), cache.Set("key3", 3))
+
+	assert.Equal(s.T(), []string{"key0", "key3", "key2"}, slotKeys(cache))
+	assert.False(s.T(), cache.data["key0"].referenced.Load(), "the hand should clear the referenced bit")
+	assert.Equal(s.T(), 2, cache.hand)
+}
+
+func (s *CacheSuite) TestCache_AllValuesReferenced_HandWrappedAround() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key0", 0))
+	require.NoError(s.T(), cache.Set("key1", 1))
+	cache.Get("key0")
+	cache.Get("key1")
+
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	assert.Equal(s.T(), []string{"key2", "key1"}, slotKeys(cache))
+	assert.Equal(s.T(), 1, cache.hand)
+}
+
+func (s *CacheSuite) TestCache_DeleteValue_SlotWasReused() {
+	cache := s.newCache(3)
+
+	for i := range 3 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+
+	assert.True(s.T(), cache.Delete("key1"))
+	require.NoError(s.T(), cache.Set("key3", 3))
+
+	assert.Equal(s.T(), []string{"key0", "key3", "key2"}, slotKeys(cache))
+	assert.Equal(s.T(), 0, cache.hand, "no entry should be evicted while a slot is free")
+	assert.Equal(s.T(), 3, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_Clear_SlotsWereFreed() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key0", 0))
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	cache.Clear()
+	require.NoError(s.T(), cache.Set("key3", 3))
+
+	assert.Equal(s.T(), []string{"key3", ""}, slotKeys(cache))
+	assert.Equal(s.T(), 0, cache.hand)
+}
+
+func (s *CacheSuite) newCache(capacity int) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+// slotKeys returns the keys of the slots in order, an empty slot is
+// returned as an empty key.
+func slotKeys(cache *Cache[string, int]) []string {
+	keys := make([]string, 0, len(cache.slots))
+	for _, v := range cache.slots {
+		if v == nil {
+			keys = append(keys, "")
+			continue
+		}
+
+		keys = append(keys, v.key)
+	}
+
+	return keys
+}
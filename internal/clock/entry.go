@@ -0,0 +1,31 @@
+package clockcache
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+	// referenced is set by hits under the read lock, so it is atomic.
+	referenced atomic.Bool
+	// slot is the index of the entry in the clock.
+	slot   int
+	expiry *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiredAt: expiredAt,
+	}
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
@@ -0,0 +1,20 @@
+package clockcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEntry(t *testing.T) {
+	t.Run("Create new entry", func(t *testing.T) {
+		expiredAt := time.Now().Add(10 * time.Second)
+		entry := newEntry("key", "value", expiredAt)
+
+		assert.Equal(t, "key", entry.key)
+		assert.Equal(t, "value", entry.value)
+		assert.Equal(t, expiredAt, entry.expiredAt)
+		assert.False(t, entry.referenced.Load())
+	})
+}
@@ -0,0 +1,408 @@
+package clockprocache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/list"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+// Cache is a CLOCK-Pro cache. Resident entries are either cold or hot, and
+// one clock also keeps the keys of recently evicted cold entries, called
+// test entries. A hit only sets the referenced bit, so hits share a read
+// lock.
+//
+// Three hands sweep the clock. The cold hand evicts unreferenced cold
+// entries, turning them into test entries, and promotes referenced ones to
+// hot. The hot hand demotes unreferenced hot entries to cold. The test hand
+// drops test entries once there are more of them than the capacity. An
+// entry set again while it is a test entry comes back hot and makes the
+// cold part larger, an expired test entry makes it smaller. Entries seen
+// only once stay cold, so a scan does not evict the hot entries.
+type Cache[K comparable, V any] struct {
+	data     map[K]*entry[K, V]
+	clock    *list.List[*entry[K, V]]
+	handHot  *list.Element[*entry[K, V]]
+	handCold *list.Element[*entry[K, V]]
+	handTest *list.Element[*entry[K, V]]
+	expiry   *lifecycle.ExpiryQueue[*entry[K, V]]
+	capacity int
+	// coldTarget is the adaptive number of resident cold entries, the rest
+	// of the capacity may be taken by hot entries.
+	coldTarget int
+	hotCount   int
+	coldCount  int
+	testCount  int
+	ttl        time.Duration
+	janitor    *janitor.Janitor
+	stats      *lifecycle.Recorder[K, V]
+	mu         sync.RWMutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.Capacity <= 0 {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	cache := Cache[K, V]{
+		data:       make(map[K]*entry[K, V], params.Capacity),
+		clock:      list.New[*entry[K, V]](),
+		expiry:     lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		capacity:   params.Capacity,
+		coldTarget: params.Capacity,
+		ttl:        params.TTL,
+		stats:      lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+// Get returns the value stored by the key. A hit only sets the referenced
+// bit of the entry, so concurrent hits share a read lock. An expired entry
+// is removed under the write lock.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	v, ok := c.data[key]
+	ok = ok && v.isResident()
+	if ok && !lifecycle.IsExpired(v.expiredAt) {
+		v.referenced.Store(true)
+		value := v.value
+		c.mu.RUnlock()
+
+		c.stats.Hit()
+		return value, true
+	}
+	c.mu.RUnlock()
+
+	if ok {
+		c.removeExpired(key)
+	}
+
+	c.stats.Miss()
+	return c.getZeroValue(), false
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok || !v.isResident() {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.hotCount + c.coldCount
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		if v.isResident() {
+			c.stats.Removal(v.key, v.value, removal.Deleted)
+		}
+	}
+
+	clear(c.data)
+	c.clock.Clear()
+	c.expiry.Clear()
+	c.handHot, c.handCold, c.handTest = nil, nil, nil
+	c.coldTarget = c.capacity
+	c.hotCount, c.coldCount, c.testCount = 0, 0, 0
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stats.Snapshot(c.hotCount + c.coldCount)
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && v.isResident() && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	switch {
+	case !ok:
+		c.addNewEntry(key, value, expiredAt, cold)
+	case v.isResident():
+		c.updateEntry(v, value, expiredAt)
+	default:
+		// The entry was evicted during its test period, a larger cold part
+		// would have kept it.
+		if c.coldTarget < c.capacity {
+			c.coldTarget++
+		}
+
+		c.removeTestEntry(v)
+		c.addNewEntry(key, value, expiredAt, hot)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	entry.referenced.Store(true)
+}
+
+// addNewEntry adds the entry right behind the hot hand, so that it is the
+// last one the hot hand reaches.
+func (c *Cache[K, V]) addNewEntry(key K, value V, expiredAt time.Time, status status) {
+	for c.hotCount+c.coldCount >= c.capacity {
+		c.evict()
+	}
+
+	entry := newEntry(key, value, expiredAt)
+	entry.status = status
+
+	if c.handHot == nil {
+		entry.element = c.clock.PushBack(entry)
+		c.handHot, c.handCold, c.handTest = entry.element, entry.element, entry.element
+	} else {
+		entry.element = c.clock.InsertBefore(entry, c.handHot)
+	}
+
+	c.data[key] = entry
+	entry.expiry = c.expiry.Push(entry)
+	if status == hot {
+		c.hotCount++
+	} else {
+		c.coldCount++
+	}
+}
+
+// evict moves the cold hand one step, then runs the hot hand until hot
+// entries fit in the part of the capacity left by the cold target and the
+// test hand until there are no more test entries than the capacity. It is
+// called until an entry is evicted.
+func (c *Cache[K, V]) evict() {
+	c.runHandCold()
+
+	for c.hotCount > c.capacity-c.coldTarget {
+		c.runHandHot()
+	}
+
+	for c.testCount > c.capacity {
+		c.runHandTest()
+	}
+}
+
+// runHandCold evicts the cold entry under the hand if it was not
+// referenced and promotes it to hot otherwise.
+func (c *Cache[K, V]) runHandCold() {
+	entry := c.handCold.Value
+	if entry.status == cold {
+		if entry.referenced.Swap(false) {
+			entry.status = hot
+			c.coldCount--
+			c.hotCount++
+		} else {
+			c.evictEntry(entry)
+		}
+	}
+
+	c.handCold = c.next(c.handCold)
+}
+
+// runHandHot demotes the hot entry under the hand to cold if it was not
+// referenced. A test entry the hot hand reaches is older than every hot
+// entry, so its test period ends and it is dropped.
+func (c *Cache[K, V]) runHandHot() {
+	entry := c.handHot.Value
+	switch entry.status {
+	case hot:
+		if !entry.referenced.Swap(false) {
+			entry.status = cold
+			c.hotCount--
+			c.coldCount++
+		}
+	case test:
+		c.dropTestEntry(entry)
+		return
+	}
+
+	c.handHot = c.next(c.handHot)
+}
+
+// runHandTest moves the test hand to the next test entry and drops it.
+func (c *Cache[K, V]) runHandTest() {
+	for c.handTest.Value.status != test {
+		c.handTest = c.next(c.handTest)
+	}
+
+	c.dropTestEntry(c.handTest.Value)
+}
+
+// dropTestEntry removes the test entry whose test period has ended. The
+// entry was not set again during the period, so the cold target shrinks.
+func (c *Cache[K, V]) dropTestEntry(entry *entry[K, V]) {
+	c.removeTestEntry(entry)
+	if c.coldTarget > 1 {
+		c.coldTarget--
+	}
+}
+
+// evictEntry turns the cold entry into a test entry. Its value is dropped
+// and only the key stays in the clock.
+func (c *Cache[K, V]) evictEntry(entry *entry[K, V]) {
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, removal.Capacity)
+
+	entry.value = c.getZeroValue()
+	entry.status = test
+	c.coldCount--
+	c.testCount++
+}
+
+// removeExpired removes the entry by the key if it is still expired once
+// the write lock is taken.
+func (c *Cache[K, V]) removeExpired(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && v.isResident() && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	c.unlink(entry)
+	if entry.status == hot {
+		c.hotCount--
+	} else {
+		c.coldCount--
+	}
+
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) removeTestEntry(entry *entry[K, V]) {
+	c.unlink(entry)
+	c.testCount--
+}
+
+// unlink removes the entry from the clock, moving the hands which point to
+// it to the next entry.
+func (c *Cache[K, V]) unlink(entry *entry[K, V]) {
+	next := c.next(entry.element)
+	if next == entry.element {
+		next = nil
+	}
+
+	if c.handHot == entry.element {
+		c.handHot = next
+	}
+	if c.handCold == entry.element {
+		c.handCold = next
+	}
+	if c.handTest == entry.element {
+		c.handTest = next
+	}
+
+	delete(c.data, entry.key)
+	c.clock.Remove(entry.element)
+}
+
+// next returns the element after e, wrapping around to the front of the
+// clock.
+func (c *Cache[K, V]) next(e *list.Element[*entry[K, V]]) *list.Element[*entry[K, V]] {
+	if next := e.Next(); next != nil {
+		return next
+	}
+
+	return c.clock.Front()
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
@@ -0,0 +1,217 @@
+package clockprocache
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	clockcache "github.com/conacry/inmem-cache/internal/clock"
+	"github.com/conacry/inmem-cache/internal/list"
+	lrucache "github.com/conacry/inmem-cache/internal/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestCache_NotEnoughCapacity_ColdValueBecameTestEntry() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key0", 0))
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	require.Contains(s.T(), cache.data, "key0")
+	assert.Equal(s.T(), test, cache.data["key0"].status)
+	assert.Zero(s.T(), cache.data["key0"].value)
+	assert.Equal(s.T(), 2, cache.Len())
+	assert.Equal(s.T(), 1, cache.testCount)
+
+	_, ok := cache.Get("key0")
+	assert.False(s.T(), ok)
+	assert.False(s.T(), cache.Delete("key0"))
+}
+
+func (s *CacheSuite) TestCache_SetTestEntry_ValueBecameHot() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key0", 0))
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	require.Equal(s.T(), test, cache.data["key0"].status)
+
+	require.NoError(s.T(), cache.Set("key0", 10))
+
+	value, ok := cache.Get("key0")
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 10, value)
+	assert.Equal(s.T(), hot, cache.data["key0"].status)
+	assert.Equal(s.T(), 2, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_ReferencedColdValue_ValueWasNotEvicted() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key0", 0))
+	require.NoError(s.T(), cache.Set("key1", 1))
+	cache.Get("key0")
+
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	_, ok := cache.Get("key0")
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), test, cache.data["key1"].status)
+}
+
+func (s *CacheSuite) TestCache_TooManyTestEntries_OldestTestEntryWasDropped() {
+	cache := s.newCache(2)
+
+	for i := range 6 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+
+	assert.Equal(s.T(), 2, cache.Len())
+	assert.Equal(s.T(), 2, cache.testCount)
+	assert.NotContains(s.T(), cache.data, "key0")
+	assert.Equal(s.T(), 4, cache.clock.Len())
+}
+
+func (s *CacheSuite) TestCache_Scan_HotValuesWereKept() {
+	cache := s.newCache(10)
+
+	hits := 0
+	for round := range 50 {
+		for i := range 3 {
+			key := fmt.Sprintf("hot%d", i)
+			if _, ok := cache.Get(key); ok {
+				if round >= 40 {
+					hits++
+				}
+				continue
+			}
+			require.NoError(s.T(), cache.Set(key, i))
+		}
+
+		for i := range 12 {
+			require.NoError(s.T(), cache.Set(fmt.Sprintf("scan%d-%d", round, i), i))
+		}
+	}
+
+	assert.Equal(s.T(), 30, hits, "hot values should survive scans which would flush an LRU cache")
+}
+
+func (s *CacheSuite) TestCache_DeleteValueUnderHands_HandsWereMoved() {
+	cache := s.newCache(3)
+
+	require.NoError(s.T(), cache.Set("key0", 0))
+	require.Same(s.T(), cache.handHot, cache.data["key0"].element)
+
+	assert.True(s.T(), cache.Delete("key0"))
+	assert.Nil(s.T(), cache.handHot)
+	assert.Nil(s.T(), cache.handCold)
+	assert.Nil(s.T(), cache.handTest)
+
+	for i := range 10 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+		cache.Delete(fmt.Sprintf("key%d", i-1))
+	}
+	s.assertConsistent(cache)
+}
+
+func (s *CacheSuite) TestCache_RandomOperations_StateIsConsistent() {
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	for capacity := 1; capacity <= 5; capacity++ {
+		cache := s.newCache(capacity)
+
+		for range 5_000 {
+			key := fmt.Sprintf("key%d", rnd.IntN(4*capacity))
+			switch rnd.IntN(4) {
+			case 0, 1:
+				require.NoError(s.T(), cache.Set(key, 0))
+			case 2:
+				cache.Get(key)
+			case 3:
+				cache.Delete(key)
+			}
+
+			s.assertConsistent(cache)
+		}
+	}
+}
+
+func (s *CacheSuite) newCache(capacity int) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+func (s *CacheSuite) assertConsistent(cache *Cache[string, int]) {
+	counts := map[status]int{}
+	for e := cache.clock.Front(); e != nil; e = e.Next() {
+		counts[e.Value.status]++
+		require.Same(s.T(), e.Value, cache.data[e.Value.key])
+	}
+
+	require.Len(s.T(), cache.data, cache.clock.Len())
+	require.Equal(s.T(), counts[hot], cache.hotCount)
+	require.Equal(s.T(), counts[cold], cache.coldCount)
+	require.Equal(s.T(), counts[test], cache.testCount)
+	require.Equal(s.T(), cache.hotCount+cache.coldCount, cache.expiry.Len())
+	require.LessOrEqual(s.T(), cache.hotCount+cache.coldCount, cache.capacity)
+	require.LessOrEqual(s.T(), cache.testCount, cache.capacity)
+
+	for _, hand := range []*list.Element[*entry[string, int]]{cache.handHot, cache.handCold, cache.handTest} {
+		if cache.clock.Len() == 0 {
+			require.Nil(s.T(), hand)
+		} else {
+			require.True(s.T(), cache.clock.Contains(hand))
+		}
+	}
+}
+
+// BenchmarkCache_HitRatio replays the same traces against CLOCK-Pro, CLOCK
+// and LRU and reports the hit ratio of each.
+func BenchmarkCache_HitRatio(b *testing.B) {
+	const capacity = 1_000
+
+	policies := []cachetest.Policy{
+		{Name: "clockpro", NewCache: func() (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+		}},
+		{Name: "clock", NewCache: func() (cachetest.Cache, error) {
+			return clockcache.NewCache[string, int](clockcache.InitParam[string, int]{Capacity: capacity})
+		}},
+		{Name: "lru", NewCache: func() (cachetest.Cache, error) {
+			return lrucache.NewCache[string, int](lrucache.InitParam[string, int]{Capacity: capacity, TTL: time.Hour})
+		}},
+	}
+
+	cachetest.BenchmarkHitRatio(b, cachetest.DefaultTraces(), policies)
+}
@@ -0,0 +1,50 @@
+package clockprocache
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/list"
+)
+
+type status int
+
+const (
+	// cold entries are resident and evicted first.
+	cold status = iota
+	// hot entries are resident and were accessed again while cold.
+	hot
+	// test entries are not resident, only their keys are kept to detect
+	// that an evicted entry is set again.
+	test
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+	status    status
+	// referenced is set by hits under the read lock, so it is atomic.
+	referenced atomic.Bool
+	element    *list.Element[*entry[K, V]]
+	// expiry links a resident entry into the expiry queue.
+	expiry *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiredAt: expiredAt,
+		status:    cold,
+	}
+}
+
+func (e *entry[K, V]) isResident() bool {
+	return e.status != test
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
@@ -0,0 +1,29 @@
+package clockprocache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEntry(t *testing.T) {
+	t.Run("Create new entry", func(t *testing.T) {
+		expiredAt := time.Now().Add(10 * time.Second)
+		entry := newEntry("key", "value", expiredAt)
+
+		assert.Equal(t, "key", entry.key)
+		assert.Equal(t, "value", entry.value)
+		assert.Equal(t, expiredAt, entry.expiredAt)
+		assert.Equal(t, cold, entry.status)
+		assert.True(t, entry.isResident())
+		assert.False(t, entry.referenced.Load())
+	})
+
+	t.Run("Test entry is not resident", func(t *testing.T) {
+		entry := newEntry("key", "value", time.Time{})
+		entry.status = test
+
+		assert.False(t, entry.isResident())
+	})
+}
@@ -0,0 +1,18 @@
+package clockprocache
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/removal"
+)
+
+type InitParam[K comparable, V any] struct {
+	Capacity int
+	// TTL is the default time to live of entries, 0 means entries never
+	// expire.
+	TTL             time.Duration
+	CleanupInterval time.Duration
+	// OnEvict is called for every entry leaving the cache. It runs while
+	// the cache lock is held, so it must not call the cache.
+	OnEvict func(key K, value V, reason removal.Reason)
+}
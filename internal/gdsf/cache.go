@@ -0,0 +1,315 @@
+package gdsfcache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+// Cache is a GreedyDual-Size-Frequency cache. Every entry has the priority
+// L + frequency × cost / size, where the cost is the price of a miss of the
+// entry, and the entry with the lowest priority is evicted first. Large
+// entries therefore leave the cache before many small hot ones, unless they
+// are expensive to fetch again.
+//
+// L is the inflation value of the cache. It is raised to the priority of
+// every evicted entry, so entries which are not accessed anymore age
+// against the new ones and are evicted eventually, whatever their past
+// frequency.
+type Cache[K comparable, V any] struct {
+	data     map[K]*entry[K, V]
+	queue    *heap.Heap[*entry[K, V]]
+	expiry   *lifecycle.ExpiryQueue[*entry[K, V]]
+	capacity int
+	maxBytes int64
+	bytes    int64
+	sizer    func(key K, value V) int64
+	cost     func(key K, value V) int64
+	// inflation is the value L added to the priority of accessed entries.
+	inflation float64
+	clock     uint64
+	ttl       time.Duration
+	janitor   *janitor.Janitor
+	stats     *lifecycle.Recorder[K, V]
+	mu        sync.Mutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.MaxBytes < 0 {
+		return nil, ErrIllegalMaxBytes
+	}
+
+	if params.Capacity < 0 || (params.Capacity == 0 && params.MaxBytes <= 0) {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.MaxBytes > 0 && params.Sizer == nil {
+		return nil, ErrSizerRequired
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	cache := Cache[K, V]{
+		data:     make(map[K]*entry[K, V], params.Capacity),
+		queue:    heap.New((*entry[K, V]).evictsBefore),
+		expiry:   lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		capacity: params.Capacity,
+		maxBytes: params.MaxBytes,
+		sizer:    params.Sizer,
+		cost:     params.Cost,
+		ttl:      params.TTL,
+		stats:    lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	c.access(v)
+	c.stats.Hit()
+	return v.value, true
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.data)
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		c.stats.Removal(v.key, v.value, removal.Deleted)
+	}
+
+	clear(c.data)
+	c.queue.Clear()
+	c.expiry.Clear()
+	c.bytes = 0
+	c.inflation = 0
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats.Snapshot(len(c.data))
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	size := c.sizeOf(key, value)
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return ErrEntryTooLarge
+	}
+
+	cost := c.costOf(key, value)
+	if cost <= 0 {
+		return ErrIllegalCost
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	if ok {
+		c.updateEntry(v, value, size, cost, expiredAt)
+	} else {
+		c.addNewEntry(key, value, size, cost, expiredAt)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+// updateEntry replaces the value of the entry. The entry leaves the queue
+// while other entries are evicted to fit its new size, so it is never
+// evicted itself.
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, size, cost int64, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	c.queue.Remove(entry.element)
+	c.bytes += size - entry.size
+	entry.size = size
+	entry.cost = cost
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	c.evictOverflow()
+
+	entry.access(c.tick(), c.inflation)
+	entry.element = c.queue.Push(entry)
+}
+
+// addNewEntry stores a new entry. Entries are evicted before the priority
+// of the new entry is computed, so it starts from the raised inflation
+// value.
+func (c *Cache[K, V]) addNewEntry(key K, value V, size, cost int64, expiredAt time.Time) {
+	entry := newEntry(key, value, expiredAt)
+	entry.size = size
+	entry.cost = cost
+	c.data[key] = entry
+	c.bytes += size
+	entry.expiry = c.expiry.Push(entry)
+	c.evictOverflow()
+
+	entry.access(c.tick(), c.inflation)
+	entry.element = c.queue.Push(entry)
+}
+
+// evictOverflow evicts entries with the lowest priority until the cache
+// fits its limits. Entries which are not in the queue are never evicted.
+func (c *Cache[K, V]) evictOverflow() {
+	for c.isOverflowed() && c.queue.Len() > 0 {
+		victim := c.queue.Peek().Value
+		c.inflation = victim.priority
+		c.removeEntry(victim, removal.Capacity)
+	}
+}
+
+func (c *Cache[K, V]) isOverflowed() bool {
+	if c.capacity > 0 && len(c.data) > c.capacity {
+		return true
+	}
+
+	return c.maxBytes > 0 && c.bytes > c.maxBytes
+}
+
+func (c *Cache[K, V]) access(entry *entry[K, V]) {
+	entry.access(c.tick(), c.inflation)
+	c.queue.Fix(entry.element)
+}
+
+func (c *Cache[K, V]) tick() uint64 {
+	c.clock++
+	return c.clock
+}
+
+func (c *Cache[K, V]) sizeOf(key K, value V) int64 {
+	if c.sizer == nil {
+		return 0
+	}
+
+	return c.sizer(key, value)
+}
+
+func (c *Cache[K, V]) costOf(key K, value V) int64 {
+	if c.cost == nil {
+		return 1
+	}
+
+	return c.cost(key, value)
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.bytes -= entry.size
+	c.queue.Remove(entry.element)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
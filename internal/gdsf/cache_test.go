@@ -0,0 +1,245 @@
+package gdsfcache
+
+import (
+	"fmt"
+	"hash/fnv"
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	lrucache "github.com/conacry/inmem-cache/internal/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, MaxBytes: -1})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalMaxBytes)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{MaxBytes: 10})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrSizerRequired)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestCache_NotEnoughBytes_LargeValueWasEvicted() {
+	cache := s.newSizedCache(10)
+
+	require.NoError(s.T(), cache.Set("small1", "12"))
+	require.NoError(s.T(), cache.Set("large", "123456"))
+	require.NoError(s.T(), cache.Set("small2", "12"))
+	require.NoError(s.T(), cache.Set("small3", "12"))
+
+	assert.Equal(s.T(), 3, cache.Len())
+	assert.Equal(s.T(), int64(6), cache.bytes)
+	_, exists := cache.Get("large")
+	assert.False(s.T(), exists, "the large value should be evicted before the small ones")
+	for _, key := range []string{"small1", "small2", "small3"} {
+		_, exists = cache.Get(key)
+		assert.True(s.T(), exists, key)
+	}
+}
+
+func (s *CacheSuite) TestCache_FrequentLargeValue_ValueWasKept() {
+	cache := s.newSizedCache(10)
+
+	require.NoError(s.T(), cache.Set("large", "123456"))
+	for range 10 {
+		cache.Get("large")
+	}
+	require.NoError(s.T(), cache.Set("small1", "12"))
+	require.NoError(s.T(), cache.Set("small2", "12"))
+	require.NoError(s.T(), cache.Set("small3", "12"))
+
+	_, exists := cache.Get("large")
+	assert.True(s.T(), exists, "the frequent value should outweigh its size")
+	_, exists = cache.Get("small1")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_ExpensiveLargeValue_ValueWasKept() {
+	params := InitParam[string, string]{
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+		Cost: func(key string, _ string) int64 {
+			if key == "large" {
+				return 100
+			}
+			return 1
+		},
+	}
+	cache, err := NewCache[string, string](params)
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), cache.Set("large", "123456"))
+	require.NoError(s.T(), cache.Set("small1", "12"))
+	require.NoError(s.T(), cache.Set("small2", "12"))
+	require.NoError(s.T(), cache.Set("small3", "12"))
+
+	_, exists := cache.Get("large")
+	assert.True(s.T(), exists, "the expensive value should outweigh its size")
+	_, exists = cache.Get("small1")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_IllegalCost_ReturnError() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		Cost:     func(string, int) int64 { return 0 },
+	}
+	cache, err := NewCache[string, int](params)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 1)
+	assert.ErrorIs(s.T(), err, ErrIllegalCost)
+	assert.Zero(s.T(), cache.Len())
+}
+
+func (s *CacheSuite) TestCache_EntryEvicted_InflationWasRaised() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	cache.Get("key2")
+	require.NoError(s.T(), cache.Set("key3", 3))
+
+	assert.Equal(s.T(), 1.0, cache.inflation)
+	assert.Equal(s.T(), 2.0, cache.data["key3"].priority)
+}
+
+func (s *CacheSuite) TestCache_FrequentValueNotAccessedAnymore_ValueWasAgedOut() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	for range 4 {
+		cache.Get("key1")
+	}
+
+	for i := 2; i <= 6; i++ {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+		_, exists := cache.data["key1"]
+		require.True(s.T(), exists, "key1 should outlive key%d", i)
+	}
+
+	require.NoError(s.T(), cache.Set("key7", 7))
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists, "new values should catch up with the old frequency")
+}
+
+func (s *CacheSuite) TestCache_NotEnoughBytes_ValuesWereEvictedUntilNewValueFits() {
+	cache := s.newSizedCache(10)
+
+	require.NoError(s.T(), cache.Set("key1", "1234"))
+	require.NoError(s.T(), cache.Set("key2", "1234"))
+	require.NoError(s.T(), cache.Set("key3", "12345678"))
+
+	assert.Equal(s.T(), 1, cache.Len())
+	assert.Equal(s.T(), int64(8), cache.bytes)
+	value, exists := cache.Get("key3")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "12345678", value)
+
+	err := cache.Set("key4", "12345678901")
+	assert.ErrorIs(s.T(), err, ErrEntryTooLarge)
+}
+
+func (s *CacheSuite) TestCache_UpdatedValueGrew_OtherValuesWereEvicted() {
+	cache := s.newSizedCache(10)
+
+	require.NoError(s.T(), cache.Set("key1", "1234"))
+	require.NoError(s.T(), cache.Set("key2", "1234"))
+	require.NoError(s.T(), cache.Set("key1", "123456789"))
+
+	assert.Equal(s.T(), 1, cache.Len())
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "123456789", value)
+	assert.Equal(s.T(), int64(9), cache.bytes)
+	assert.Equal(s.T(), 1, cache.queue.Len())
+}
+
+func (s *CacheSuite) TestCache_Clear_InflationWasReset() {
+	cache := s.newCache(1)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	require.NotZero(s.T(), cache.inflation)
+
+	cache.Clear()
+
+	assert.Zero(s.T(), cache.inflation)
+	assert.Zero(s.T(), cache.bytes)
+	assert.Equal(s.T(), 0, cache.queue.Len())
+}
+
+func (s *CacheSuite) newCache(capacity int) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+func (s *CacheSuite) newSizedCache(maxBytes int64) *Cache[string, string] {
+	cache, err := NewCache[string, string](InitParam[string, string]{
+		MaxBytes: maxBytes,
+		Sizer:    stringSizer,
+	})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+func stringSizer(_ string, value string) int64 {
+	return int64(len(value))
+}
+
+// keySizer gives every key a stable size from 1 to 100 bytes.
+func keySizer(key string, _ int) int64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return int64(h.Sum32()%100) + 1
+}
+
+// BenchmarkCache_HitRatio replays the same traces against GDSF and LRU,
+// both limited to the same number of bytes, and reports the hit ratio of
+// each.
+func BenchmarkCache_HitRatio(b *testing.B) {
+	const maxBytes = 50_000
+
+	traces := cachetest.DefaultTraces()[:2]
+	policies := []cachetest.Policy{
+		{Name: "gdsf", NewCache: func() (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{MaxBytes: maxBytes, Sizer: keySizer})
+		}},
+		{Name: "lru", NewCache: func() (cachetest.Cache, error) {
+			return lrucache.NewCache[string, int](lrucache.InitParam[string, int]{MaxBytes: maxBytes, Sizer: keySizer, TTL: time.Hour})
+		}},
+	}
+
+	cachetest.BenchmarkHitRatio(b, traces, policies)
+}
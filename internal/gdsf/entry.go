@@ -0,0 +1,55 @@
+package gdsfcache
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+	size      int64
+	cost      int64
+	freq      uint64
+	priority  float64
+	// lastAccess is the logical time of the last access, it orders entries
+	// with equal priorities.
+	lastAccess uint64
+	element    *heap.Element[*entry[K, V]]
+	expiry     *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiredAt: expiredAt,
+		cost:      1,
+	}
+}
+
+// access counts an access at the logical time now and recomputes the
+// priority over the inflation value of the cache. The priority grows with
+// the frequency and the cost of a miss and falls with the size. Entries of
+// size 0 are treated as entries of size 1.
+func (e *entry[K, V]) access(now uint64, inflation float64) {
+	e.freq++
+	e.lastAccess = now
+	e.priority = inflation + float64(e.freq)*float64(e.cost)/float64(max(e.size, 1))
+}
+
+// evictsBefore reports whether e has a lower priority than other. Entries
+// with equal priorities are evicted in LRU order.
+func (e *entry[K, V]) evictsBefore(other *entry[K, V]) bool {
+	if e.priority != other.priority {
+		return e.priority < other.priority
+	}
+
+	return e.lastAccess < other.lastAccess
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
@@ -0,0 +1,78 @@
+package gdsfcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEntry(t *testing.T) {
+	t.Run("Create new entry", func(t *testing.T) {
+		expiredAt := time.Now().Add(10 * time.Second)
+		entry := newEntry("key", "value", expiredAt)
+
+		assert.Equal(t, "key", entry.key)
+		assert.Equal(t, "value", entry.value)
+		assert.Equal(t, expiredAt, entry.expiredAt)
+		assert.Equal(t, int64(1), entry.cost)
+		assert.Zero(t, entry.freq)
+		assert.Zero(t, entry.priority)
+		assert.Nil(t, entry.element)
+	})
+}
+
+func TestEntry_Access(t *testing.T) {
+	t.Run("Priority grows with frequency and falls with size", func(t *testing.T) {
+		entry := newEntry("key", "value", time.Time{})
+		entry.size = 4
+
+		entry.access(1, 0)
+		assert.Equal(t, uint64(1), entry.freq)
+		assert.Equal(t, 0.25, entry.priority)
+
+		entry.access(2, 10)
+		assert.Equal(t, uint64(2), entry.freq)
+		assert.Equal(t, uint64(2), entry.lastAccess)
+		assert.Equal(t, 10.5, entry.priority)
+	})
+
+	t.Run("Priority grows with cost", func(t *testing.T) {
+		entry := newEntry("key", "value", time.Time{})
+		entry.size = 4
+		entry.cost = 10
+
+		entry.access(1, 0)
+		assert.Equal(t, 2.5, entry.priority)
+	})
+
+	t.Run("Entry of size 0 is treated as entry of size 1", func(t *testing.T) {
+		entry := newEntry("key", "value", time.Time{})
+
+		entry.access(1, 0)
+		assert.Equal(t, 1.0, entry.priority)
+	})
+
+	t.Run("Entry with lower priority is evicted first", func(t *testing.T) {
+		large := newEntry("large", 1, time.Time{})
+		large.size = 10
+		large.access(2, 0)
+
+		small := newEntry("small", 2, time.Time{})
+		small.size = 1
+		small.access(1, 0)
+
+		assert.True(t, large.evictsBefore(small))
+		assert.False(t, small.evictsBefore(large))
+	})
+
+	t.Run("Entries with equal priorities are ordered by last access", func(t *testing.T) {
+		first := newEntry("first", 1, time.Time{})
+		first.access(1, 0)
+
+		second := newEntry("second", 2, time.Time{})
+		second.access(2, 0)
+
+		assert.True(t, first.evictsBefore(second))
+	})
+}
@@ -0,0 +1,118 @@
+package heap
+
+import (
+	"container/heap"
+)
+
+// Element is an element of a Heap.
+type Element[T any] struct {
+	Value T
+	index int
+	heap  *Heap[T]
+}
+
+// Heap is a binary min-heap used by eviction policies to keep entries
+// ordered by expiration time or priority. Elements remember their position,
+// so an element can be fixed or removed in O(log n) after its value changes.
+type Heap[T any] struct {
+	items items[T]
+}
+
+// New returns an empty heap ordered by less, the element for which less
+// reports true against every other one is on top.
+func New[T any](less func(a, b T) bool) *Heap[T] {
+	return &Heap[T]{
+		items: items[T]{less: less},
+	}
+}
+
+func (h *Heap[T]) Len() int {
+	return len(h.items.elements)
+}
+
+func (h *Heap[T]) Push(v T) *Element[T] {
+	e := &Element[T]{Value: v, heap: h}
+	heap.Push(&h.items, e)
+
+	return e
+}
+
+// Peek returns the top element of the heap, or nil if the heap is empty.
+func (h *Heap[T]) Peek() *Element[T] {
+	if len(h.items.elements) == 0 {
+		return nil
+	}
+
+	return h.items.elements[0]
+}
+
+// Fix restores the order after the value of e has changed. It does nothing
+// if e does not belong to the heap.
+func (h *Heap[T]) Fix(e *Element[T]) {
+	if !h.Contains(e) {
+		return
+	}
+
+	heap.Fix(&h.items, e.index)
+}
+
+// Remove removes e from the heap. It does nothing if e does not belong to
+// the heap.
+func (h *Heap[T]) Remove(e *Element[T]) {
+	if !h.Contains(e) {
+		return
+	}
+
+	heap.Remove(&h.items, e.index)
+}
+
+// Contains reports whether e belongs to the heap.
+func (h *Heap[T]) Contains(e *Element[T]) bool {
+	return e != nil && e.heap == h
+}
+
+// Clear removes all elements.
+func (h *Heap[T]) Clear() {
+	for _, e := range h.items.elements {
+		e.heap = nil
+	}
+
+	clear(h.items.elements)
+	h.items.elements = h.items.elements[:0]
+}
+
+// items implements heap.Interface.
+type items[T any] struct {
+	elements []*Element[T]
+	less     func(a, b T) bool
+}
+
+func (s items[T]) Len() int {
+	return len(s.elements)
+}
+
+func (s items[T]) Less(i, j int) bool {
+	return s.less(s.elements[i].Value, s.elements[j].Value)
+}
+
+func (s items[T]) Swap(i, j int) {
+	s.elements[i], s.elements[j] = s.elements[j], s.elements[i]
+	s.elements[i].index = i
+	s.elements[j].index = j
+}
+
+func (s *items[T]) Push(x any) {
+	e := x.(*Element[T])
+	e.index = len(s.elements)
+	s.elements = append(s.elements, e)
+}
+
+func (s *items[T]) Pop() any {
+	n := len(s.elements)
+	e := s.elements[n-1]
+	s.elements[n-1] = nil
+	e.heap = nil
+	s.elements = s.elements[:n-1]
+
+	return e
+}
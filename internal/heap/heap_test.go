@@ -0,0 +1,143 @@
+package heap
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HeapSuite struct {
+	suite.Suite
+}
+
+func TestHeapSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(HeapSuite))
+}
+
+type item struct {
+	priority int
+}
+
+func (s *HeapSuite) TestNew_ReturnEmptyHeap() {
+	h := newHeap()
+	require.NotNil(s.T(), h)
+	assert.Equal(s.T(), 0, h.Len())
+	assert.Nil(s.T(), h.Peek())
+}
+
+func (s *HeapSuite) TestPeek_ReturnLeastElement() {
+	h := newHeap()
+
+	late := h.Push(&item{priority: 3})
+	soon := h.Push(&item{priority: 1})
+	middle := h.Push(&item{priority: 2})
+
+	assert.Equal(s.T(), 3, h.Len())
+	assert.Equal(s.T(), soon, h.Peek())
+
+	h.Remove(soon)
+	assert.Equal(s.T(), middle, h.Peek())
+
+	h.Remove(middle)
+	assert.Equal(s.T(), late, h.Peek())
+}
+
+func (s *HeapSuite) TestFix_ValueWasChanged_OrderWasRestored() {
+	h := newHeap()
+
+	first := h.Push(&item{priority: 1})
+	second := h.Push(&item{priority: 2})
+	require.Equal(s.T(), first, h.Peek())
+
+	first.Value.priority = 3
+	h.Fix(first)
+	assert.Equal(s.T(), second, h.Peek())
+}
+
+func (s *HeapSuite) TestRemove_ElementWasRemoved() {
+	h := newHeap()
+
+	first := h.Push(&item{priority: 1})
+	second := h.Push(&item{priority: 2})
+
+	h.Remove(first)
+	assert.Equal(s.T(), 1, h.Len())
+	assert.False(s.T(), h.Contains(first))
+	assert.True(s.T(), h.Contains(second))
+	assert.Equal(s.T(), second, h.Peek())
+
+	assert.NotPanics(s.T(), func() {
+		h.Remove(first)
+		h.Fix(first)
+		h.Remove(nil)
+		h.Fix(nil)
+	})
+	assert.Equal(s.T(), 1, h.Len())
+}
+
+func (s *HeapSuite) TestRemove_ElementOfOtherHeap_NothingWasRemoved() {
+	h := newHeap()
+	other := newHeap()
+
+	h.Push(&item{priority: 1})
+	e := other.Push(&item{priority: 2})
+
+	h.Remove(e)
+	assert.Equal(s.T(), 1, h.Len())
+	assert.True(s.T(), other.Contains(e))
+}
+
+func (s *HeapSuite) TestClear_HeapIsEmpty() {
+	h := newHeap()
+
+	first := h.Push(&item{priority: 1})
+	h.Push(&item{priority: 2})
+	h.Clear()
+
+	assert.Equal(s.T(), 0, h.Len())
+	assert.Nil(s.T(), h.Peek())
+	assert.False(s.T(), h.Contains(first))
+}
+
+func (s *HeapSuite) TestRandomOperations_ElementsWerePoppedInOrder() {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	h := newHeap()
+
+	var elements []*Element[*item]
+	for range 1_000 {
+		switch rnd.IntN(3) {
+		case 0:
+			elements = append(elements, h.Push(&item{priority: rnd.IntN(100)}))
+		case 1:
+			if len(elements) > 0 {
+				e := elements[rnd.IntN(len(elements))]
+				e.Value.priority = rnd.IntN(100)
+				h.Fix(e)
+			}
+		case 2:
+			if len(elements) > 0 {
+				i := rnd.IntN(len(elements))
+				h.Remove(elements[i])
+				elements = append(elements[:i], elements[i+1:]...)
+			}
+		}
+	}
+
+	require.Equal(s.T(), len(elements), h.Len())
+	prev := -1
+	for e := h.Peek(); e != nil; e = h.Peek() {
+		require.GreaterOrEqual(s.T(), e.Value.priority, prev)
+		prev = e.Value.priority
+		h.Remove(e)
+	}
+}
+
+func newHeap() *Heap[*item] {
+	return New(func(a, b *item) bool {
+		return a.priority < b.priority
+	})
+}
@@ -0,0 +1,50 @@
+package janitor
+
+import (
+	"sync"
+	"time"
+)
+
+// Janitor periodically runs a cleanup function in a background goroutine
+// until it is stopped.
+type Janitor struct {
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func New(interval time.Duration, cleanup func()) *Janitor {
+	j := Janitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go j.run(interval, cleanup)
+
+	return &j
+}
+
+// Stop terminates the background goroutine and waits for it to exit.
+// It is safe to call Stop more than once.
+func (j *Janitor) Stop() {
+	j.stopOnce.Do(func() {
+		close(j.stop)
+	})
+	<-j.done
+}
+
+func (j *Janitor) run(interval time.Duration, cleanup func()) {
+	defer close(j.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			cleanup()
+		case <-j.stop:
+			return
+		}
+	}
+}
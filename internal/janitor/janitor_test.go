@@ -0,0 +1,47 @@
+package janitor
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJanitor(t *testing.T) {
+	t.Run("Cleanup runs periodically", func(t *testing.T) {
+		var calls atomic.Int32
+		j := New(10*time.Millisecond, func() {
+			calls.Add(1)
+		})
+		require.NotNil(t, j)
+		defer j.Stop()
+
+		assert.Eventually(t, func() bool {
+			return calls.Load() >= 2
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Cleanup does not run after stop", func(t *testing.T) {
+		var calls atomic.Int32
+		j := New(10*time.Millisecond, func() {
+			calls.Add(1)
+		})
+		require.NotNil(t, j)
+
+		j.Stop()
+		callsAfterStop := calls.Load()
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, callsAfterStop, calls.Load())
+	})
+
+	t.Run("Stop can be called twice", func(t *testing.T) {
+		j := New(time.Hour, func() {})
+		require.NotNil(t, j)
+
+		j.Stop()
+		assert.NotPanics(t, j.Stop)
+	})
+}
@@ -2,69 +2,352 @@ package lfucache
 
 import (
 	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/cacheerr"
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/readbuf"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
 )
 
 type Cache[K comparable, V any] struct {
-	data     map[K]*entry[V]
-	freq     *frequencySet[K]
+	data     map[K]*entry[K, V]
+	freq     *frequencySet[K, V]
+	expiry   *lifecycle.ExpiryQueue[*entry[K, V]]
 	capacity int
-	mu       sync.Mutex
+	cost     int64
+	maxBytes int64
+	bytes    int64
+	sizer    func(key K, value V) int64
+	ttl      time.Duration
+	janitor  *janitor.Janitor
+	// decayJanitor periodically decays use counts, it is nil if they never
+	// decay.
+	decayJanitor *janitor.Janitor
+	decayFactor  float64
+	stats        *lifecycle.Recorder[K, V]
+	reads        *readbuf.Buffer[entry[K, V]]
+	mu           sync.RWMutex
 }
 
-func NewCache[K comparable, V any](params InitParam) (*Cache[K, V], error) {
-	if params.Capacity <= 0 {
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.MaxBytes < 0 {
+		return nil, ErrIllegalMaxBytes
+	}
+
+	if params.Capacity < 0 || (params.Capacity == 0 && params.MaxBytes <= 0) {
 		return nil, ErrIllegalCapacity
 	}
 
+	if params.MaxBytes > 0 && params.Sizer == nil {
+		return nil, ErrSizerRequired
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	if params.DecayInterval < 0 {
+		return nil, ErrIllegalDecayInterval
+	}
+
+	if params.DecayInterval > 0 && (params.DecayFactor <= 0 || params.DecayFactor >= 1) {
+		return nil, ErrIllegalDecayFactor
+	}
+
 	cache := Cache[K, V]{
-		data:     make(map[K]*entry[V], params.Capacity),
-		freq:     newFrequencySet[K](),
-		capacity: params.Capacity,
+		data:        make(map[K]*entry[K, V], params.Capacity),
+		freq:        newFrequencySet[K, V](),
+		expiry:      lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		capacity:    params.Capacity,
+		maxBytes:    params.MaxBytes,
+		sizer:       params.Sizer,
+		ttl:         params.TTL,
+		stats:       lifecycle.NewRecorder(params.OnEvict),
+		decayFactor: params.DecayFactor,
+	}
+
+	if params.BufferedReads {
+		cache.reads = readbuf.New[entry[K, V]]()
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	if params.DecayInterval > 0 {
+		cache.decayJanitor = janitor.New(params.DecayInterval, cache.decay)
 	}
 
 	return &cache, nil
 }
 
 func (c *Cache[K, V]) Get(key K) (V, bool) {
+	if c.reads != nil {
+		return c.getBuffered(key)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	v, ok := c.data[key]
 	if !ok {
+		c.stats.Miss()
 		return c.getZeroValue(), false
 	}
 
-	c.freq.Touch(key, v.useCount)
-	v.useCount++
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	c.freq.Touch(v)
+	c.stats.Hit()
 	return v.value, true
 }
 
+// getBuffered looks the entry up under the read lock and records the access
+// into the read buffer instead of updating the frequencies, so concurrent hits
+// do not contend. The buffer is drained by whoever holds the write lock.
+func (c *Cache[K, V]) getBuffered(key K) (V, bool) {
+	c.mu.RLock()
+	v, ok := c.data[key]
+	if ok && !lifecycle.IsExpired(v.expiredAt) {
+		value := v.value
+		c.mu.RUnlock()
+
+		if c.reads.Add(v) && c.mu.TryLock() {
+			c.drainReads()
+			c.mu.Unlock()
+		}
+
+		c.stats.Hit()
+		return value, true
+	}
+	c.mu.RUnlock()
+
+	if ok {
+		c.removeExpired(key)
+	}
+
+	c.stats.Miss()
+	return c.getZeroValue(), false
+}
+
+// removeExpired removes the entry by the key if it is still expired once
+// the write lock is taken.
+func (c *Cache[K, V]) removeExpired(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+// drainReads applies buffered accesses. Entries removed after the access
+// was recorded are skipped.
+func (c *Cache[K, V]) drainReads() {
+	if c.reads == nil {
+		return
+	}
+
+	c.reads.Drain(func(v *entry[K, V]) {
+		if c.data[v.key] == v {
+			c.freq.Touch(v)
+		}
+	})
+}
+
 func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, 1, lifecycle.ExpirationTime(c.ttl))
+}
+
+// SetWithCost stores the value with the given cost and the default TTL. The
+// capacity of the cache limits the total cost of entries, Set, SetWithTTL
+// and SetWithDeadline store entries with cost 1. Without the capacity the
+// cost would limit nothing, so ErrCostCapacityRequired is returned.
+func (c *Cache[K, V]) SetWithCost(key K, value V, cost int64) error {
+	if cost <= 0 {
+		return ErrIllegalCost
+	}
+
+	if c.capacity == 0 {
+		return ErrCostCapacityRequired
+	}
+
+	return c.set(key, value, cost, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, 1, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, 1, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if len(c.data) >= c.capacity {
-		c.evictLessUsedEntry()
+	v, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
 	}
 
-	if _, ok := c.data[key]; ok {
-		c.updateEntry(key, value)
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.data)
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		c.stats.Removal(v.key, v.value, removal.Deleted)
+	}
+
+	clear(c.data)
+	c.freq.Clear()
+	c.expiry.Clear()
+	c.cost = 0
+	c.bytes = 0
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stats.Snapshot(len(c.data))
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+// Close stops the decay of use counts as well.
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+
+	if c.decayJanitor != nil {
+		c.decayJanitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, cost int64, expiredAt time.Time) error {
+	if c.capacity > 0 && cost > int64(c.capacity) {
+		return &cacheerr.CostTooLargeError{Cost: cost, Capacity: int64(c.capacity)}
+	}
+
+	size := c.sizeOf(key, value)
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return ErrEntryTooLarge
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.drainReads()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	if ok {
+		c.updateEntry(v, value, cost, size, expiredAt)
 	} else {
-		c.addNewEntry(key, value)
+		c.addNewEntry(key, value, cost, size, expiredAt)
 	}
 
+	c.stats.Set()
 	return nil
 }
 
-func (c *Cache[K, V]) updateEntry(key K, value V) {
-	entry := c.data[key]
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, cost, size int64, expiredAt time.Time) {
+	c.makeRoom(cost-entry.cost, size-entry.size, entry)
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	c.cost += cost - entry.cost
+	c.bytes += size - entry.size
 	entry.value = value
+	entry.cost = cost
+	entry.size = size
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+}
+
+func (c *Cache[K, V]) addNewEntry(key K, value V, cost, size int64, expiredAt time.Time) {
+	c.makeRoom(cost, size, nil)
+
+	entry := newEntry(key, value, expiredAt)
+	entry.cost = cost
+	entry.size = size
+
+	c.data[key] = entry
+	c.cost += cost
+	c.bytes += size
+	c.freq.Add(entry)
+	entry.expiry = c.expiry.Push(entry)
+}
+
+// makeRoom evicts entries until cost and bytes more fit into the cache.
+// The except entry is never evicted.
+func (c *Cache[K, V]) makeRoom(cost, bytes int64, except *entry[K, V]) {
+	for c.isOverflowed(cost, bytes) {
+		if !c.evictLessUsedEntry(except) {
+			return
+		}
+	}
 }
 
-func (c *Cache[K, V]) addNewEntry(key K, value V) {
-	entry := newEntry(value)
-	c.data[key] = &entry
-	c.freq.Add(key)
+func (c *Cache[K, V]) isOverflowed(cost, bytes int64) bool {
+	if c.capacity > 0 && c.cost+cost > int64(c.capacity) {
+		return true
+	}
+
+	return c.maxBytes > 0 && c.bytes+bytes > c.maxBytes
+}
+
+func (c *Cache[K, V]) sizeOf(key K, value V) int64 {
+	if c.sizer == nil {
+		return 0
+	}
+
+	return c.sizer(key, value)
 }
 
 func (c *Cache[K, T]) getZeroValue() T {
@@ -72,9 +355,52 @@ func (c *Cache[K, T]) getZeroValue() T {
 	return zeroValue
 }
 
-func (c *Cache[K, V]) evictLessUsedEntry() {
-	keyToRemove := c.freq.GetLeastFrequent()
-	entry := c.data[keyToRemove]
-	delete(c.data, keyToRemove)
-	c.freq.Remove(keyToRemove, entry.useCount)
+// evictLessUsedEntry evicts an expired entry if there is one, and the least
+// frequently used entry otherwise. It reports whether an entry was evicted.
+func (c *Cache[K, V]) evictLessUsedEntry(except *entry[K, V]) bool {
+	if expired, ok := c.expiry.Expired(); ok && expired != except {
+		c.removeEntry(expired, removal.Expired)
+		return true
+	}
+
+	entryToRemove := c.freq.GetLeastFrequentExcept(except)
+	if entryToRemove == nil {
+		return false
+	}
+
+	c.removeEntry(entryToRemove, removal.Capacity)
+	return true
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.cost -= entry.cost
+	c.bytes -= entry.size
+	c.freq.Remove(entry)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+// decay multiplies use counts of all entries by the decay factor. Buffered
+// accesses are applied first, so they decay as well.
+func (c *Cache[K, V]) decay() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.drainReads()
+	c.freq.Decay(c.decayFactor)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
 }
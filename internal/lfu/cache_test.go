@@ -1,8 +1,14 @@
 package lfucache
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/conacry/inmem-cache/internal/cacheerr"
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -20,7 +26,7 @@ func TestCacheSuite(t *testing.T) {
 func (s *CacheSuite) TestNewCache_IllegalCapacity_ReturnError() {
 	illegalCapacity := 0
 
-	params := InitParam{Capacity: illegalCapacity}
+	params := InitParam[string, struct{}]{Capacity: illegalCapacity}
 	cache, err := NewCache[string, struct{}](params)
 	assert.Nil(s.T(), cache)
 	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
@@ -29,7 +35,7 @@ func (s *CacheSuite) TestNewCache_IllegalCapacity_ReturnError() {
 func (s *CacheSuite) TestNewCache_CorrectCapacity_ReturnCache() {
 	correctCapacity := 50
 
-	params := InitParam{Capacity: correctCapacity}
+	params := InitParam[string, struct{}]{Capacity: correctCapacity}
 	cache, err := NewCache[string, struct{}](params)
 	require.NotNil(s.T(), cache)
 	require.NoError(s.T(), err)
@@ -41,7 +47,7 @@ func (s *CacheSuite) TestCache_TwoValuesWithTheSameFrequency_ReturnTwoValues() {
 		Field2 int
 	}
 
-	params := InitParam{Capacity: 2}
+	params := InitParam[string, StructForCache]{Capacity: 2}
 	cache, err := NewCache[string, StructForCache](params)
 	require.NotNil(s.T(), cache)
 	require.NoError(s.T(), err)
@@ -79,7 +85,7 @@ func (s *CacheSuite) TestCache_ThreeValuesWithDifferentFrequency_ReturnTwoValues
 		Field2 int
 	}
 
-	params := InitParam{Capacity: 2}
+	params := InitParam[string, StructForCache]{Capacity: 2}
 	cache, err := NewCache[string, StructForCache](params)
 	require.NotNil(s.T(), cache)
 	require.NoError(s.T(), err)
@@ -130,7 +136,7 @@ func (s *CacheSuite) TestCache_SetValueByTheSameKey_ValueWasUpdated() {
 		Field2 int
 	}
 
-	params := InitParam{Capacity: 2}
+	params := InitParam[string, StructForCache]{Capacity: 2}
 	cache, err := NewCache[string, StructForCache](params)
 	require.NotNil(s.T(), cache)
 	require.NoError(s.T(), err)
@@ -160,3 +166,751 @@ func (s *CacheSuite) TestCache_SetValueByTheSameKey_ValueWasUpdated() {
 	require.True(s.T(), ok)
 	require.Equal(s.T(), value2, v2)
 }
+
+func (s *CacheSuite) TestCache_DeleteLeastFrequentKey_NextEvictionKeepsOtherValues() {
+	params := InitParam[string, int]{Capacity: 2}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	key1 := "key1"
+	key2 := "key2"
+	key3 := "key3"
+
+	err = cache.Set(key1, 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set(key2, 100501)
+	require.NoError(s.T(), err)
+
+	_, ok := cache.Get(key2)
+	require.True(s.T(), ok)
+
+	deleted := cache.Delete(key1)
+	assert.True(s.T(), deleted)
+	assert.Equal(s.T(), 1, cache.Len())
+
+	err = cache.Set(key3, 100502)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	v2, ok := cache.Get(key2)
+	require.True(s.T(), ok)
+	require.Equal(s.T(), 100501, v2)
+
+	v3, ok := cache.Get(key3)
+	require.True(s.T(), ok)
+	require.Equal(s.T(), 100502, v3)
+}
+
+func (s *CacheSuite) TestCache_DeleteNotExistedKey_ReturnFalse() {
+	params := InitParam[string, int]{Capacity: 1}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	deleted := cache.Delete("not_existed_key")
+	assert.False(s.T(), deleted)
+}
+
+func (s *CacheSuite) TestCache_Clear_AllValuesWereRemoved() {
+	params := InitParam[string, int]{Capacity: 2}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 100501)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 2, cache.Len())
+
+	cache.Clear()
+	assert.Equal(s.T(), 0, cache.Len())
+
+	_, ok := cache.Get("key1")
+	assert.False(s.T(), ok)
+
+	err = cache.Set("key3", 100502)
+	require.NoError(s.T(), err)
+	err = cache.Set("key4", 100503)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_ValuesWithTheSameFrequency_LeastRecentlyUsedWasEvicted() {
+	params := InitParam[string, int]{Capacity: 2}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 100501)
+	require.NoError(s.T(), err)
+
+	_, ok := cache.Get("key2")
+	require.True(s.T(), ok)
+	_, ok = cache.Get("key1")
+	require.True(s.T(), ok)
+
+	err = cache.Set("key3", 100502)
+	require.NoError(s.T(), err)
+
+	_, ok = cache.Get("key2")
+	assert.False(s.T(), ok)
+
+	v1, ok := cache.Get("key1")
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 100500, v1)
+}
+
+func (s *CacheSuite) TestCache_UpdateValueInFullCache_NothingWasEvicted() {
+	params := InitParam[string, int]{Capacity: 2}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 100501)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key2", 100502)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	v1, ok := cache.Get("key1")
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 100500, v1)
+
+	v2, ok := cache.Get("key2")
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 100502, v2)
+}
+
+func BenchmarkCache_SetWithEviction(b *testing.B) {
+	for _, capacity := range []int{1_000, 10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("capacity=%d", capacity), func(b *testing.B) {
+			params := InitParam[int, int]{Capacity: capacity}
+			cache, err := NewCache[int, int](params)
+			require.NoError(b, err)
+
+			for i := 0; i < capacity; i++ {
+				err = cache.Set(i, i)
+				require.NoError(b, err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = cache.Set(capacity+i, i)
+			}
+		})
+	}
+}
+
+func (s *CacheSuite) TestNewCache_IllegalTTL_ReturnError() {
+	params := InitParam[string, struct{}]{
+		Capacity: 50,
+		TTL:      -time.Second,
+	}
+	cache, err := NewCache[string, struct{}](params)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+}
+
+func (s *CacheSuite) TestNewCache_IllegalCleanupInterval_ReturnError() {
+	params := InitParam[string, struct{}]{
+		Capacity:        50,
+		CleanupInterval: -time.Second,
+	}
+	cache, err := NewCache[string, struct{}](params)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestNewCache_IllegalDecayParams_ReturnError() {
+	params := InitParam[string, struct{}]{
+		Capacity:      50,
+		DecayInterval: -time.Second,
+	}
+	cache, err := NewCache[string, struct{}](params)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalDecayInterval)
+
+	for _, factor := range []float64{-0.5, 0, 1, 2} {
+		params = InitParam[string, struct{}]{
+			Capacity:      50,
+			DecayInterval: time.Second,
+			DecayFactor:   factor,
+		}
+		cache, err = NewCache[string, struct{}](params)
+		assert.Nil(s.T(), cache)
+		assert.ErrorIs(s.T(), err, ErrIllegalDecayFactor, "factor: %v", factor)
+	}
+}
+
+func (s *CacheSuite) TestCache_Decay_FormerlyHotValueWasEvicted() {
+	params := InitParam[string, int]{
+		Capacity:      2,
+		DecayInterval: time.Hour,
+		DecayFactor:   0.5,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+	defer cache.Close()
+
+	require.NoError(s.T(), cache.Set("old", 1))
+	for range 8 {
+		cache.Get("old")
+	}
+
+	require.NoError(s.T(), cache.Set("new", 2))
+	for range 3 {
+		cache.Get("new")
+	}
+
+	cache.decay()
+	cache.decay()
+	assert.Equal(s.T(), 2, cache.data["old"].useCount())
+	assert.Equal(s.T(), 0, cache.data["new"].useCount())
+
+	cache.Get("new")
+	cache.Get("new")
+	cache.Get("new")
+	require.NoError(s.T(), cache.Set("key3", 3))
+
+	_, exists := cache.Get("old")
+	assert.False(s.T(), exists, "the decayed value should be evicted")
+	_, exists = cache.Get("new")
+	assert.True(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_WithDecayInterval_UseCountsWereDecayed() {
+	params := InitParam[string, int]{
+		Capacity:      2,
+		DecayInterval: 10 * time.Millisecond,
+		DecayFactor:   0.1,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+	defer cache.Close()
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	for range 5 {
+		cache.Get("key1")
+	}
+
+	assert.Eventually(s.T(), func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+
+		return cache.data["key1"].useCount() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *CacheSuite) TestCache_BufferedReadsBeforeDecay_AccessesWereDecayed() {
+	params := InitParam[string, int]{
+		Capacity:      2,
+		BufferedReads: true,
+		DecayInterval: time.Hour,
+		DecayFactor:   0.5,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+	defer cache.Close()
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	for range 4 {
+		cache.Get("key1")
+	}
+
+	cache.decay()
+
+	assert.Equal(s.T(), 2, cache.data["key1"].useCount())
+}
+
+func (s *CacheSuite) TestCache_TtlIsExpired_CacheWasNotReturnStoredValue() {
+	ttl := 50 * time.Millisecond
+	params := InitParam[string, int]{
+		Capacity: 2,
+		TTL:      ttl,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+
+	v1, ok := cache.Get("key1")
+	require.True(s.T(), ok)
+	require.Equal(s.T(), 100500, v1)
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	v1, ok = cache.Get("key1")
+	assert.False(s.T(), ok)
+	assert.Empty(s.T(), v1)
+	assert.Equal(s.T(), 0, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_NotEnoughCapacity_ExpiredValueWasEvictedBeforeLessUsedOne() {
+	params := InitParam[string, int]{Capacity: 2}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	ttl := 50 * time.Millisecond
+	err = cache.SetWithTTL("frequent", 100500, ttl)
+	require.NoError(s.T(), err)
+	err = cache.Set("rare", 100501)
+	require.NoError(s.T(), err)
+
+	for i := 0; i < 3; i++ {
+		_, ok := cache.Get("frequent")
+		require.True(s.T(), ok)
+	}
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	err = cache.Set("new", 100502)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	v, ok := cache.Get("rare")
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 100501, v)
+
+	_, ok = cache.Get("frequent")
+	assert.False(s.T(), ok)
+}
+
+func (s *CacheSuite) TestCache_SetWithTTL_ValueExpiredByOwnTTL() {
+	params := InitParam[string, int]{
+		Capacity: 2,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	ttl := 50 * time.Millisecond
+	err = cache.SetWithTTL("key1", 100500, ttl)
+	require.NoError(s.T(), err)
+
+	deadline := time.Now().Add(ttl)
+	err = cache.SetWithDeadline("key2", 100501, deadline)
+	require.NoError(s.T(), err)
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	_, ok := cache.Get("key1")
+	assert.False(s.T(), ok)
+
+	_, ok = cache.Get("key2")
+	assert.False(s.T(), ok)
+}
+
+func (s *CacheSuite) TestCache_SetWithIllegalExpiration_ReturnError() {
+	params := InitParam[string, int]{Capacity: 2}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithTTL("key1", 100500, -time.Second)
+	assert.ErrorIs(s.T(), err, ErrIllegalEntryTTL)
+
+	err = cache.SetWithDeadline("key1", 100500, time.Now().Add(-time.Second))
+	assert.ErrorIs(s.T(), err, ErrIllegalDeadline)
+	assert.Equal(s.T(), 0, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_WithCleanupInterval_ExpiredValueWasRemovedWithoutAccess() {
+	params := InitParam[string, int]{
+		Capacity:        2,
+		TTL:             50 * time.Millisecond,
+		CleanupInterval: 10 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+	defer cache.Close()
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, cache.Len())
+
+	assert.Eventually(s.T(), func() bool {
+		return cache.Len() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *CacheSuite) TestCache_WithOnEvict_CallbackReceivedRemovalReasons() {
+	recorder := &evictionRecorder{}
+	ttl := 50 * time.Millisecond
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      ttl,
+		OnEvict:  recorder.record,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set("key1", 100501)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 100502)
+	require.NoError(s.T(), err)
+	deleted := cache.Delete("key2")
+	require.True(s.T(), deleted)
+
+	err = cache.Set("key3", 100503)
+	require.NoError(s.T(), err)
+	time.Sleep(ttl + 20*time.Millisecond)
+	_, exists := cache.Get("key3")
+	require.False(s.T(), exists)
+
+	err = cache.Set("key4", 100504)
+	require.NoError(s.T(), err)
+	cache.Clear()
+
+	expected := []eviction{
+		{key: "key1", value: 100500, reason: removal.Replaced},
+		{key: "key1", value: 100501, reason: removal.Capacity},
+		{key: "key2", value: 100502, reason: removal.Deleted},
+		{key: "key3", value: 100503, reason: removal.Expired},
+		{key: "key4", value: 100504, reason: removal.Deleted},
+	}
+	assert.Equal(s.T(), expected, recorder.evictions)
+}
+
+type eviction struct {
+	key    string
+	value  int
+	reason removal.Reason
+}
+
+type evictionRecorder struct {
+	evictions []eviction
+}
+
+func (r *evictionRecorder) record(key string, value int, reason removal.Reason) {
+	r.evictions = append(r.evictions, eviction{key: key, value: value, reason: reason})
+}
+
+func (s *CacheSuite) TestCache_Stats_CountersWereCollected() {
+	ttl := 50 * time.Millisecond
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      ttl,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	_, exists := cache.Get("key1")
+	require.True(s.T(), exists)
+	_, exists = cache.Get("not_existed_key")
+	require.False(s.T(), exists)
+
+	err = cache.Set("key2", 100501)
+	require.NoError(s.T(), err)
+	time.Sleep(ttl + 20*time.Millisecond)
+	_, exists = cache.Get("key2")
+	require.False(s.T(), exists)
+
+	expected := stats.Stats{
+		Hits:        1,
+		Misses:      2,
+		Sets:        2,
+		Evictions:   1,
+		Expirations: 1,
+		Size:        0,
+	}
+	assert.Equal(s.T(), expected, cache.Stats())
+	assert.InDelta(s.T(), 1.0/3.0, cache.Stats().HitRatio(), 0.001)
+
+	cache.ResetStats()
+	assert.Equal(s.T(), stats.Stats{}, cache.Stats())
+}
+
+func (s *CacheSuite) TestNewCache_IllegalMaxBytes_ReturnError() {
+	params := InitParam[string, string]{
+		MaxBytes: -1,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalMaxBytes)
+}
+
+func (s *CacheSuite) TestNewCache_MaxBytesWithoutSizer_ReturnError() {
+	params := InitParam[string, string]{
+		MaxBytes: 100,
+	}
+	cache, err := NewCache[string, string](params)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrSizerRequired)
+}
+
+func (s *CacheSuite) TestCache_NotEnoughBytes_ValuesWereEvictedUntilNewValueFits() {
+	params := InitParam[string, string]{
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "1234")
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", "1234")
+	require.NoError(s.T(), err)
+	err = cache.Set("key3", "12")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 3, cache.Len())
+
+	err = cache.Set("key4", "12345678")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists)
+	_, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+
+	value, exists := cache.Get("key3")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "12", value)
+
+	value, exists = cache.Get("key4")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "12345678", value)
+}
+
+func (s *CacheSuite) TestCache_UpdatedValueGrew_OtherValuesWereEvicted() {
+	params := InitParam[string, string]{
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "1234")
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", "1234")
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "123456789")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, cache.Len())
+
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "123456789", value)
+}
+
+func (s *CacheSuite) TestCache_ValueLargerThanMaxBytes_ReturnError() {
+	params := InitParam[string, string]{
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "1234")
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key2", "12345678901")
+	assert.ErrorIs(s.T(), err, ErrEntryTooLarge)
+	assert.Equal(s.T(), 1, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_SetWithCost_ValuesWereEvictedUntilCostFits() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", 1, 4)
+	require.NoError(s.T(), err)
+	err = cache.SetWithCost("key2", 2, 4)
+	require.NoError(s.T(), err)
+	err = cache.Set("key3", 3)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 3, cache.Len())
+
+	err = cache.SetWithCost("key4", 4, 8)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists)
+	_, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+
+	value, exists := cache.Get("key3")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 3, value)
+
+	value, exists = cache.Get("key4")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 4, value)
+}
+
+func (s *CacheSuite) TestCache_SetWithCostUpdatedCostGrew_OtherValuesWereEvicted() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", 1, 4)
+	require.NoError(s.T(), err)
+	err = cache.SetWithCost("key2", 2, 4)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", 10, 9)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, cache.Len())
+
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 10, value)
+}
+
+func (s *CacheSuite) TestCache_SetWithCostLargerThanCapacity_ReturnError() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key2", 2, 11)
+	var costErr *cacheerr.CostTooLargeError
+	require.ErrorAs(s.T(), err, &costErr)
+	assert.Equal(s.T(), int64(11), costErr.Cost)
+	assert.Equal(s.T(), int64(10), costErr.Capacity)
+	assert.Equal(s.T(), 1, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_SetWithCostWithoutCapacity_ReturnError() {
+	params := InitParam[string, string]{
+		MaxBytes: 100,
+		Sizer:    stringSizer,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", "value1", 5)
+	assert.ErrorIs(s.T(), err, ErrCostCapacityRequired)
+	assert.Zero(s.T(), cache.Len())
+}
+
+func (s *CacheSuite) TestCache_SetWithIllegalCost_ReturnError() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", 1, 0)
+	assert.ErrorIs(s.T(), err, ErrIllegalCost)
+	err = cache.SetWithCost("key1", 1, -1)
+	assert.ErrorIs(s.T(), err, ErrIllegalCost)
+	assert.Zero(s.T(), cache.Len())
+}
+
+func (s *CacheSuite) TestCache_DeleteWeightedValue_CostWasReleased() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", 1, 8)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), cache.Delete("key1"))
+
+	err = cache.SetWithCost("key2", 2, 5)
+	require.NoError(s.T(), err)
+	err = cache.SetWithCost("key3", 3, 5)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+}
+
+func stringSizer(_ string, value string) int64 {
+	return int64(len(value))
+}
+
+func (s *CacheSuite) TestCache_BufferedReads_AccessWasAppliedBeforeEviction() {
+	params := InitParam[string, int]{
+		Capacity:      2,
+		TTL:           time.Hour,
+		BufferedReads: true,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 2)
+	require.NoError(s.T(), err)
+
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 1, value)
+
+	err = cache.Set("key3", 3)
+	require.NoError(s.T(), err)
+
+	_, exists = cache.Get("key1")
+	assert.True(s.T(), exists)
+	_, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+	_, exists = cache.Get("key3")
+	assert.True(s.T(), exists)
+}
+
+// BenchmarkCache_BufferedReads compares the default and the buffered read
+// path, see cachetest.BenchmarkBufferedReads.
+func BenchmarkCache_BufferedReads(b *testing.B) {
+	cachetest.BenchmarkBufferedReads(b,
+		func(capacity int, buffered bool) (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{
+				Capacity:      capacity,
+				TTL:           time.Hour,
+				BufferedReads: buffered,
+			})
+		},
+		func(cache cachetest.Cache) uint64 {
+			return cache.(*Cache[string, int]).reads.Dropped()
+		},
+	)
+}
@@ -1,13 +1,39 @@
 package lfucache
 
-type entry[T any] struct {
-	value    T
-	useCount int
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+	cost      int64
+	size      int64
+	node      *frequencyNode[K, V]
+	prev      *entry[K, V]
+	next      *entry[K, V]
+	expiry    *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiredAt: expiredAt,
+	}
 }
 
-func newEntry[T any](value T) entry[T] {
-	return entry[T]{
-		value:    value,
-		useCount: 0,
+func (e *entry[K, V]) useCount() int {
+	if e.node == nil {
+		return 0
 	}
+
+	return e.node.count
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
 }
@@ -2,17 +2,24 @@ package lfucache
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewEntry(t *testing.T) {
 	t.Run("Create new entry", func(t *testing.T) {
+		key := "key"
 		value := "value"
-		entry := newEntry(value)
+		expiredAt := time.Now().Add(10 * time.Second)
+		entry := newEntry(key, value, expiredAt)
 
 		assert.NotEmpty(t, entry)
+		assert.Equal(t, key, entry.key)
 		assert.Equal(t, value, entry.value)
-		assert.Equal(t, 0, entry.useCount)
+		assert.Equal(t, expiredAt, entry.expiredAt)
+		assert.Nil(t, entry.node)
+		assert.Equal(t, 0, entry.useCount())
+		assert.Nil(t, entry.expiry)
 	})
 }
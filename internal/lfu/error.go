@@ -2,8 +2,22 @@ package lfucache
 
 import (
 	"errors"
+
+	"github.com/conacry/inmem-cache/internal/cacheerr"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
 )
 
 var (
-	ErrIllegalCapacity = errors.New("capacity should be greater than 0")
+	ErrIllegalCapacity        = errors.New("capacity should be greater than 0 unless max bytes is set")
+	ErrIllegalTTL             = errors.New("ttl should not be negative")
+	ErrIllegalCleanupInterval = errors.New("cleanup interval should not be negative")
+	ErrIllegalEntryTTL        = lifecycle.ErrIllegalEntryTTL
+	ErrIllegalDeadline        = lifecycle.ErrIllegalDeadline
+	ErrIllegalMaxBytes        = errors.New("max bytes should not be negative")
+	ErrSizerRequired          = errors.New("sizer is required when max bytes is set")
+	ErrEntryTooLarge          = errors.New("entry size exceeds max bytes")
+	ErrIllegalCost            = errors.New("cost should be greater than 0")
+	ErrCostCapacityRequired   = cacheerr.ErrCostCapacityRequired
+	ErrIllegalDecayInterval   = errors.New("decay interval should not be negative")
+	ErrIllegalDecayFactor     = errors.New("decay factor should be greater than 0 and less than 1")
 )
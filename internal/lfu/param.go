@@ -1,5 +1,35 @@
 package lfucache
 
-type InitParam struct {
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/removal"
+)
+
+type InitParam[K comparable, V any] struct {
+	// Capacity limits the total cost of entries, which is the number of
+	// entries unless SetWithCost is used. 0 means the cache is limited by
+	// MaxBytes only.
 	Capacity int
+	// MaxBytes limits the total size of entries measured by Sizer, 0 means
+	// there is no limit.
+	MaxBytes int64
+	Sizer    func(key K, value V) int64
+	// TTL is the default time to live of entries, 0 means entries never
+	// expire.
+	TTL             time.Duration
+	CleanupInterval time.Duration
+	// OnEvict is called for every entry leaving the cache. It runs while
+	// the cache lock is held, so it must not call the cache.
+	OnEvict func(key K, value V, reason removal.Reason)
+	// BufferedReads makes Get record accesses into a lossy read buffer
+	// instead of updating the eviction order under the write lock.
+	BufferedReads bool
+	// DecayInterval is how often use counts of all entries are multiplied
+	// by DecayFactor, so entries which were hot long ago can be evicted.
+	// 0 means use counts never decay.
+	DecayInterval time.Duration
+	// DecayFactor is greater than 0 and less than 1 if DecayInterval is
+	// set, 0.5 halves use counts.
+	DecayFactor float64
 }
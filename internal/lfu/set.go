@@ -1,59 +1,178 @@
 package lfucache
 
-import (
-	"time"
-)
+// frequencySet is the classic O(1) LFU structure: an ascending list of
+// frequency nodes, each holding the entries used exactly node.count times.
+// Entries inside a node are kept from the least to the most recently used
+// one, which makes the eviction order deterministic for equal frequencies.
+type frequencySet[K comparable, V any] struct {
+	root frequencyNode[K, V]
+}
+
+type frequencyNode[K comparable, V any] struct {
+	count   int
+	entries entryList[K, V]
+	prev    *frequencyNode[K, V]
+	next    *frequencyNode[K, V]
+}
 
-type bucket[V comparable] map[V]time.Time
+func newFrequencySet[K comparable, V any]() *frequencySet[K, V] {
+	set := frequencySet[K, V]{}
+	set.root.next = &set.root
+	set.root.prev = &set.root
 
-type frequencySet[V comparable] struct {
-	data     map[int]bucket[V]
-	minCount int
+	return &set
 }
 
-func newFrequencySet[V comparable]() *frequencySet[V] {
-	return &frequencySet[V]{
-		data: make(map[int]bucket[V]),
+func (f *frequencySet[K, V]) Add(e *entry[K, V]) {
+	node := f.root.next
+	if node == &f.root || node.count != 0 {
+		node = f.insertNodeAfter(&f.root, 0)
 	}
+
+	node.entries.PushBack(e)
+	e.node = node
 }
 
-func (f *frequencySet[V]) Add(value V) {
-	if _, ok := f.data[0]; !ok {
-		f.data[0] = make(bucket[V])
+func (f *frequencySet[K, V]) Touch(e *entry[K, V]) {
+	node := e.node
+	nextNode := node.next
+	if nextNode == &f.root || nextNode.count != node.count+1 {
+		nextNode = f.insertNodeAfter(node, node.count+1)
+	}
+
+	node.entries.Remove(e)
+	nextNode.entries.PushBack(e)
+	e.node = nextNode
+
+	if node.entries.Len() == 0 {
+		f.removeNode(node)
 	}
-	f.data[0][value] = time.Now()
-	f.minCount = 0
 }
 
-func (f *frequencySet[V]) Touch(value V, count int) {
-	delete(f.data[count], value)
+func (f *frequencySet[K, V]) GetLeastFrequent() *entry[K, V] {
+	return f.GetLeastFrequentExcept(nil)
+}
 
-	if _, ok := f.data[count+1]; !ok {
-		f.data[count+1] = make(bucket[V])
+// GetLeastFrequentExcept returns the least frequently used entry other than
+// except, or nil if there is no such entry.
+func (f *frequencySet[K, V]) GetLeastFrequentExcept(except *entry[K, V]) *entry[K, V] {
+	node := f.root.next
+	if node == &f.root {
+		return nil
 	}
 
-	f.data[count+1][value] = time.Now()
+	e := node.entries.Front()
+	if e != except {
+		return e
+	}
 
-	if count == f.minCount && len(f.data[count]) == 0 {
-		f.minCount++
+	if e.next != &node.entries.root {
+		return e.next
 	}
+
+	if node.next == &f.root {
+		return nil
+	}
+
+	return node.next.entries.Front()
 }
 
-func (f *frequencySet[V]) GetLeastFrequent() V {
-	minCreatedAt := time.Now()
-	var minValue V
+func (f *frequencySet[K, V]) Remove(e *entry[K, V]) {
+	node := e.node
+	if node == nil {
+		return
+	}
 
-	bucket := f.data[f.minCount]
-	for value, createdAt := range bucket {
-		if createdAt.Before(minCreatedAt) {
-			minCreatedAt = createdAt
-			minValue = value
+	node.entries.Remove(e)
+	e.node = nil
+
+	if node.entries.Len() == 0 {
+		f.removeNode(node)
+	}
+}
+
+// Decay multiplies use counts of all entries by the factor, rounding down.
+// Nodes whose counts become equal are merged, entries of the node which was
+// less frequent stay in front, so the eviction order is kept.
+func (f *frequencySet[K, V]) Decay(factor float64) {
+	for node := f.root.next; node != &f.root; {
+		next := node.next
+		node.count = int(float64(node.count) * factor)
+
+		if prev := node.prev; prev != &f.root && prev.count == node.count {
+			for e := node.entries.Front(); e != nil; e = node.entries.Front() {
+				node.entries.Remove(e)
+				prev.entries.PushBack(e)
+				e.node = prev
+			}
+			f.removeNode(node)
 		}
+
+		node = next
 	}
+}
+
+func (f *frequencySet[K, V]) Clear() {
+	f.root.next = &f.root
+	f.root.prev = &f.root
+}
+
+func (f *frequencySet[K, V]) insertNodeAfter(mark *frequencyNode[K, V], count int) *frequencyNode[K, V] {
+	node := &frequencyNode[K, V]{count: count}
+	node.entries.init()
+
+	node.prev = mark
+	node.next = mark.next
+	mark.next.prev = node
+	mark.next = node
+
+	return node
+}
+
+func (f *frequencySet[K, V]) removeNode(node *frequencyNode[K, V]) {
+	node.prev.next = node.next
+	node.next.prev = node.prev
+	node.prev = nil
+	node.next = nil
+}
+
+// entryList is an intrusive doubly linked list of entries with the same
+// frequency, ordered from the least to the most recently used one.
+type entryList[K comparable, V any] struct {
+	root entry[K, V]
+	len  int
+}
+
+func (l *entryList[K, V]) init() {
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	l.len = 0
+}
+
+func (l *entryList[K, V]) Len() int {
+	return l.len
+}
+
+func (l *entryList[K, V]) Front() *entry[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+
+	return l.root.next
+}
 
-	return minValue
+func (l *entryList[K, V]) PushBack(e *entry[K, V]) {
+	e.prev = l.root.prev
+	e.next = &l.root
+	l.root.prev.next = e
+	l.root.prev = e
+	l.len++
 }
 
-func (f *frequencySet[V]) Remove(value V, count int) {
-	delete(f.data[count], value)
+func (l *entryList[K, V]) Remove(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+	e.next = nil
+	l.len--
 }
@@ -1,7 +1,9 @@
 package lfucache
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,113 +19,156 @@ func TestFrequencySetSuite(t *testing.T) {
 	suite.Run(t, new(FrequencySetSuite))
 }
 
-func (s *FrequencySetSuite) TestNewFrequencySet_ReturnFrequencySet() {
-	set := newFrequencySet[string]()
+func (s *FrequencySetSuite) TestNewFrequencySet_ReturnEmptyFrequencySet() {
+	set := newFrequencySet[string, struct{}]()
 	require.NotNil(s.T(), set)
-	assert.NotNil(s.T(), set.data)
-	assert.Equal(s.T(), 0, set.minCount)
+	assert.Nil(s.T(), set.GetLeastFrequent())
+	assert.Empty(s.T(), nodeCounts(set))
 }
 
 func (s *FrequencySetSuite) TestAdd_NewValueWasAdded() {
-	set := newFrequencySet[string]()
+	set := newFrequencySet[string, struct{}]()
 	require.NotNil(s.T(), set)
 
-	value := "key1"
-	set.Add(value)
+	entry := newEntry("key1", struct{}{}, time.Time{})
+	set.Add(entry)
 
-	bucket, ok := set.data[0]
-	assert.True(s.T(), ok)
-	assert.NotNil(s.T(), bucket)
-
-	createdAt, ok := set.data[0][value]
-	assert.True(s.T(), ok)
-	assert.NotEmpty(s.T(), createdAt)
+	require.NotNil(s.T(), entry.node)
+	assert.Equal(s.T(), 0, entry.useCount())
+	assert.Equal(s.T(), []int{0}, nodeCounts(set))
+	assert.Equal(s.T(), entry, set.GetLeastFrequent())
 }
 
 func (s *FrequencySetSuite) TestTouch_FrequencyCountIncreased() {
-	set := newFrequencySet[string]()
+	set := newFrequencySet[string, struct{}]()
 	require.NotNil(s.T(), set)
 
-	value := "key1"
-	set.Add(value)
-	bucket, ok := set.data[0]
-	require.True(s.T(), ok)
-	assert.NotNil(s.T(), bucket)
+	entry := newEntry("key1", struct{}{}, time.Time{})
+	set.Add(entry)
+	set.Touch(entry)
+
+	assert.Equal(s.T(), 1, entry.useCount())
+	assert.Equal(s.T(), []int{1}, nodeCounts(set))
+}
 
-	_, ok = set.data[0][value]
-	require.True(s.T(), ok)
+func (s *FrequencySetSuite) TestGetLeastFrequent_ReturnLessUsedValue() {
+	set := newFrequencySet[string, struct{}]()
+	require.NotNil(s.T(), set)
 
-	set.Touch(value, 0)
+	entryOne := newEntry("key1", struct{}{}, time.Time{})
+	set.Add(entryOne)
+	set.Touch(entryOne)
+	set.Touch(entryOne)
+	set.Touch(entryOne)
 
-	_, ok = set.data[0][value]
-	assert.False(s.T(), ok)
+	entryTwo := newEntry("key2", struct{}{}, time.Time{})
+	set.Add(entryTwo)
+	set.Touch(entryTwo)
+	set.Touch(entryTwo)
 
-	_, ok = set.data[1][value]
-	assert.True(s.T(), ok)
+	assert.Equal(s.T(), []int{2, 3}, nodeCounts(set))
+	assert.Equal(s.T(), entryTwo, set.GetLeastFrequent())
 }
 
-func (s *FrequencySetSuite) TestGetLeastFrequent_ReturnLessUsedValue() {
-	set := newFrequencySet[string]()
+func (s *FrequencySetSuite) TestGetLeastFrequent_SameFrequency_ReturnLeastRecentlyUsedValue() {
+	set := newFrequencySet[string, struct{}]()
 	require.NotNil(s.T(), set)
 
-	valueOne := "key1"
-	countOne := 0
-	set.Add(valueOne)
-
-	_, ok := set.data[countOne][valueOne]
-	require.True(s.T(), ok)
+	entryOne := newEntry("key1", struct{}{}, time.Time{})
+	entryTwo := newEntry("key2", struct{}{}, time.Time{})
+	set.Add(entryOne)
+	set.Add(entryTwo)
+	assert.Equal(s.T(), entryOne, set.GetLeastFrequent())
+
+	set.Touch(entryOne)
+	set.Touch(entryTwo)
+	assert.Equal(s.T(), entryOne, set.GetLeastFrequent())
+
+	set.Touch(entryOne)
+	set.Touch(entryTwo)
+	set.Touch(entryTwo)
+	set.Touch(entryOne)
+	assert.Equal(s.T(), entryTwo, set.GetLeastFrequent())
+}
 
-	set.Touch(valueOne, countOne)
-	countOne++
-	set.Touch(valueOne, countOne)
-	countOne++
-	set.Touch(valueOne, countOne)
+func (s *FrequencySetSuite) TestRemove_ValueWasRemoved() {
+	set := newFrequencySet[string, struct{}]()
+	require.NotNil(s.T(), set)
 
-	valueTwo := "key2"
-	countTwo := 0
-	set.Add(valueTwo)
+	entryOne := newEntry("key1", struct{}{}, time.Time{})
+	set.Add(entryOne)
+	set.Touch(entryOne)
+	set.Touch(entryOne)
 
-	_, ok = set.data[countTwo][valueTwo]
-	require.True(s.T(), ok)
+	entryTwo := newEntry("key2", struct{}{}, time.Time{})
+	set.Add(entryTwo)
+	assert.Equal(s.T(), entryTwo, set.GetLeastFrequent())
 
-	set.Touch(valueTwo, 0)
-	set.Touch(valueTwo, 1)
+	set.Remove(entryTwo)
+	assert.Nil(s.T(), entryTwo.node)
+	assert.Equal(s.T(), []int{2}, nodeCounts(set))
+	assert.Equal(s.T(), entryOne, set.GetLeastFrequent())
 
-	leastFrequent := set.GetLeastFrequent()
-	assert.Equal(s.T(), valueTwo, leastFrequent)
+	assert.NotPanics(s.T(), func() {
+		set.Remove(entryTwo)
+	})
 }
 
-func (s *FrequencySetSuite) TestRemove_ValueWasRemoved() {
-	set := newFrequencySet[string]()
+func (s *FrequencySetSuite) TestDecay_CountsWereDecayedAndNodesMerged() {
+	set := newFrequencySet[string, struct{}]()
 	require.NotNil(s.T(), set)
 
-	valueOne := "key1"
-	countOne := 0
-	set.Add(valueOne)
+	entries := make(map[int]*entry[string, struct{}])
+	for _, count := range []int{0, 1, 2, 3, 8} {
+		entry := newEntry(fmt.Sprintf("key%d", count), struct{}{}, time.Time{})
+		set.Add(entry)
+		for range count {
+			set.Touch(entry)
+		}
+		entries[count] = entry
+	}
+	require.Equal(s.T(), []int{0, 1, 2, 3, 8}, nodeCounts(set))
+
+	set.Decay(0.5)
+
+	assert.Equal(s.T(), []int{0, 1, 4}, nodeCounts(set))
+	assert.Equal(s.T(), 0, entries[1].useCount())
+	assert.Equal(s.T(), 1, entries[3].useCount())
+	assert.Equal(s.T(), 4, entries[8].useCount())
+	assert.Equal(s.T(), []string{"key0", "key1"}, nodeKeys(set.root.next))
+	assert.Equal(s.T(), []string{"key2", "key3"}, nodeKeys(set.root.next.next), "less frequent entries should stay in front")
+
+	set.Touch(entries[2])
+	assert.Equal(s.T(), 2, entries[2].useCount())
+	assert.Equal(s.T(), []int{0, 1, 2, 4}, nodeCounts(set))
+}
 
-	_, ok := set.data[countOne][valueOne]
-	require.True(s.T(), ok)
+func (s *FrequencySetSuite) TestClear_SetIsEmpty() {
+	set := newFrequencySet[string, struct{}]()
+	require.NotNil(s.T(), set)
 
-	set.Touch(valueOne, countOne)
-	countOne++
-	set.Touch(valueOne, countOne)
-	countOne++
-	set.Touch(valueOne, countOne)
+	set.Add(newEntry("key1", struct{}{}, time.Time{}))
+	set.Add(newEntry("key2", struct{}{}, time.Time{}))
+	set.Clear()
 
-	valueTwo := "key2"
-	countTwo := 0
-	set.Add(valueTwo)
+	assert.Nil(s.T(), set.GetLeastFrequent())
+	assert.Empty(s.T(), nodeCounts(set))
+}
 
-	_, ok = set.data[countTwo][valueTwo]
-	require.True(s.T(), ok)
+func nodeKeys[V any](node *frequencyNode[string, V]) []string {
+	var keys []string
+	for e := node.entries.Front(); e != nil && e != &node.entries.root; e = e.next {
+		keys = append(keys, e.key)
+	}
 
-	set.Touch(valueTwo, 0)
-	set.Touch(valueTwo, 1)
+	return keys
+}
 
-	leastFrequent := set.GetLeastFrequent()
-	assert.Equal(s.T(), valueTwo, leastFrequent)
+func nodeCounts[K comparable, V any](set *frequencySet[K, V]) []int {
+	var counts []int
+	for node := set.root.next; node != &set.root; node = node.next {
+		counts = append(counts, node.count)
+	}
 
-	set.Remove(valueTwo, countTwo)
-	_, ok = set.data[countTwo][valueTwo]
-	assert.False(s.T(), ok)
+	return counts
 }
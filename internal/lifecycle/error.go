@@ -0,0 +1,10 @@
+package lifecycle
+
+import (
+	"errors"
+)
+
+var (
+	ErrIllegalEntryTTL = errors.New("entry ttl should not be negative")
+	ErrIllegalDeadline = errors.New("deadline should be in the future")
+)
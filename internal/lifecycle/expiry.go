@@ -0,0 +1,56 @@
+package lifecycle
+
+import (
+	"time"
+)
+
+// ExpirationTime returns the moment when an entry with the given ttl
+// expires, or the zero time if ttl is 0 and the entry never expires.
+func ExpirationTime(ttl time.Duration) time.Time {
+	if ttl == 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(ttl)
+}
+
+// EntryExpirationTime returns the expiration time of an entry stored by
+// SetWithTTL. The entry never expires if ttl is 0.
+func EntryExpirationTime(ttl time.Duration) (time.Time, error) {
+	if ttl < 0 {
+		return time.Time{}, ErrIllegalEntryTTL
+	}
+
+	return ExpirationTime(ttl), nil
+}
+
+// CheckDeadline validates the deadline of an entry stored by
+// SetWithDeadline. The zero time is valid and means the entry never
+// expires.
+func CheckDeadline(deadline time.Time) error {
+	if !deadline.IsZero() && !deadline.After(time.Now()) {
+		return ErrIllegalDeadline
+	}
+
+	return nil
+}
+
+// IsExpired reports whether an entry with the given expiration time has
+// expired. The zero time never expires.
+func IsExpired(expiredAt time.Time) bool {
+	return !expiredAt.IsZero() && time.Now().After(expiredAt)
+}
+
+// ExpiresBefore reports whether expiration time a comes earlier than b.
+// The zero time comes last.
+func ExpiresBefore(a, b time.Time) bool {
+	if a.IsZero() {
+		return false
+	}
+
+	if b.IsZero() {
+		return true
+	}
+
+	return a.Before(b)
+}
@@ -0,0 +1,83 @@
+package lifecycle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpirationTime(t *testing.T) {
+	t.Run("Zero ttl means no expiration", func(t *testing.T) {
+		assert.True(t, ExpirationTime(0).IsZero())
+	})
+
+	t.Run("Positive ttl returns time in the future", func(t *testing.T) {
+		assert.True(t, ExpirationTime(time.Minute).After(time.Now()))
+	})
+}
+
+func TestEntryExpirationTime(t *testing.T) {
+	t.Run("Negative ttl is rejected", func(t *testing.T) {
+		_, err := EntryExpirationTime(-time.Second)
+		assert.ErrorIs(t, err, ErrIllegalEntryTTL)
+	})
+
+	t.Run("Zero ttl means no expiration", func(t *testing.T) {
+		expiredAt, err := EntryExpirationTime(0)
+		require.NoError(t, err)
+		assert.True(t, expiredAt.IsZero())
+	})
+
+	t.Run("Positive ttl returns time in the future", func(t *testing.T) {
+		expiredAt, err := EntryExpirationTime(time.Minute)
+		require.NoError(t, err)
+		assert.True(t, expiredAt.After(time.Now()))
+	})
+}
+
+func TestCheckDeadline(t *testing.T) {
+	t.Run("Past deadline is rejected", func(t *testing.T) {
+		assert.ErrorIs(t, CheckDeadline(time.Now().Add(-time.Second)), ErrIllegalDeadline)
+	})
+
+	t.Run("Zero deadline means no expiration", func(t *testing.T) {
+		assert.NoError(t, CheckDeadline(time.Time{}))
+	})
+
+	t.Run("Future deadline is accepted", func(t *testing.T) {
+		assert.NoError(t, CheckDeadline(time.Now().Add(time.Minute)))
+	})
+}
+
+func TestIsExpired(t *testing.T) {
+	t.Run("Zero time never expires", func(t *testing.T) {
+		assert.False(t, IsExpired(time.Time{}))
+	})
+
+	t.Run("Future time is not expired", func(t *testing.T) {
+		assert.False(t, IsExpired(time.Now().Add(time.Minute)))
+	})
+
+	t.Run("Past time is expired", func(t *testing.T) {
+		assert.True(t, IsExpired(time.Now().Add(-time.Second)))
+	})
+}
+
+func TestExpiresBefore(t *testing.T) {
+	t.Run("Earlier time expires before", func(t *testing.T) {
+		now := time.Now()
+
+		assert.True(t, ExpiresBefore(now, now.Add(time.Second)))
+		assert.False(t, ExpiresBefore(now.Add(time.Second), now))
+	})
+
+	t.Run("Zero time expires last", func(t *testing.T) {
+		now := time.Now()
+
+		assert.True(t, ExpiresBefore(now, time.Time{}))
+		assert.False(t, ExpiresBefore(time.Time{}, now))
+		assert.False(t, ExpiresBefore(time.Time{}, time.Time{}))
+	})
+}
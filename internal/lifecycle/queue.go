@@ -0,0 +1,59 @@
+package lifecycle
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+)
+
+// ExpiryQueue orders cache entries by expiration time, so expired entries
+// are found without scanning the whole cache. Entries which never expire
+// are kept at the end of the queue.
+type ExpiryQueue[T any] struct {
+	heap      *heap.Heap[T]
+	expiredAt func(v T) time.Time
+}
+
+// NewExpiryQueue returns an empty queue. expiredAt returns the expiration
+// time of an entry, the zero time means the entry never expires.
+func NewExpiryQueue[T any](expiredAt func(v T) time.Time) *ExpiryQueue[T] {
+	return &ExpiryQueue[T]{
+		heap: heap.New(func(a, b T) bool {
+			return ExpiresBefore(expiredAt(a), expiredAt(b))
+		}),
+		expiredAt: expiredAt,
+	}
+}
+
+func (q *ExpiryQueue[T]) Len() int {
+	return q.heap.Len()
+}
+
+func (q *ExpiryQueue[T]) Push(v T) *heap.Element[T] {
+	return q.heap.Push(v)
+}
+
+// Fix restores the order after the expiration time of e has changed.
+func (q *ExpiryQueue[T]) Fix(e *heap.Element[T]) {
+	q.heap.Fix(e)
+}
+
+func (q *ExpiryQueue[T]) Remove(e *heap.Element[T]) {
+	q.heap.Remove(e)
+}
+
+// Expired returns the entry which has expired first. It returns false if
+// no entry has expired yet.
+func (q *ExpiryQueue[T]) Expired() (T, bool) {
+	first := q.heap.Peek()
+	if first == nil || !IsExpired(q.expiredAt(first.Value)) {
+		var zeroValue T
+		return zeroValue, false
+	}
+
+	return first.Value, true
+}
+
+func (q *ExpiryQueue[T]) Clear() {
+	q.heap.Clear()
+}
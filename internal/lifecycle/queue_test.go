@@ -0,0 +1,91 @@
+package lifecycle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type expiring struct {
+	name      string
+	expiredAt time.Time
+}
+
+func newExpiryQueue() *ExpiryQueue[*expiring] {
+	return NewExpiryQueue(func(v *expiring) time.Time {
+		return v.expiredAt
+	})
+}
+
+func TestExpiryQueue(t *testing.T) {
+	t.Run("Empty queue has no expired entries", func(t *testing.T) {
+		q := newExpiryQueue()
+
+		_, ok := q.Expired()
+		assert.False(t, ok)
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("Entry which expired first is returned", func(t *testing.T) {
+		q := newExpiryQueue()
+		q.Push(&expiring{name: "eternal"})
+		q.Push(&expiring{name: "alive", expiredAt: time.Now().Add(time.Minute)})
+		q.Push(&expiring{name: "late", expiredAt: time.Now().Add(-time.Second)})
+		q.Push(&expiring{name: "early", expiredAt: time.Now().Add(-time.Minute)})
+
+		v, ok := q.Expired()
+		require.True(t, ok)
+		assert.Equal(t, "early", v.name)
+	})
+
+	t.Run("Alive entries are not returned", func(t *testing.T) {
+		q := newExpiryQueue()
+		q.Push(&expiring{name: "eternal"})
+		q.Push(&expiring{name: "alive", expiredAt: time.Now().Add(time.Minute)})
+
+		_, ok := q.Expired()
+		assert.False(t, ok)
+	})
+
+	t.Run("Removed entry is not returned", func(t *testing.T) {
+		q := newExpiryQueue()
+		e := q.Push(&expiring{name: "expired", expiredAt: time.Now().Add(-time.Second)})
+
+		q.Remove(e)
+
+		_, ok := q.Expired()
+		assert.False(t, ok)
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("Entry is reordered after its expiration time has changed", func(t *testing.T) {
+		q := newExpiryQueue()
+		v := &expiring{name: "renewed", expiredAt: time.Now().Add(-time.Second)}
+		e := q.Push(v)
+
+		v.expiredAt = time.Now().Add(time.Minute)
+		q.Fix(e)
+
+		_, ok := q.Expired()
+		assert.False(t, ok)
+
+		v.expiredAt = time.Now().Add(-time.Second)
+		q.Fix(e)
+
+		_, ok = q.Expired()
+		assert.True(t, ok)
+	})
+
+	t.Run("Clear removes all entries", func(t *testing.T) {
+		q := newExpiryQueue()
+		q.Push(&expiring{name: "expired", expiredAt: time.Now().Add(-time.Second)})
+
+		q.Clear()
+
+		_, ok := q.Expired()
+		assert.False(t, ok)
+		assert.Equal(t, 0, q.Len())
+	})
+}
@@ -0,0 +1,30 @@
+package lifecycle
+
+import (
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+// Recorder collects statistics of a cache and passes removed entries to
+// the eviction callback, so the counters and the callback always agree.
+// It is safe for concurrent use as long as the callback is.
+type Recorder[K comparable, V any] struct {
+	stats.Counter
+	onEvict func(key K, value V, reason removal.Reason)
+}
+
+// NewRecorder returns a recorder calling onEvict for every removed entry.
+// onEvict may be nil.
+func NewRecorder[K comparable, V any](onEvict func(key K, value V, reason removal.Reason)) *Recorder[K, V] {
+	return &Recorder[K, V]{
+		onEvict: onEvict,
+	}
+}
+
+// Removal records that the entry has left the cache for the reason.
+func (r *Recorder[K, V]) Removal(key K, value V, reason removal.Reason) {
+	r.Counter.Removal(reason)
+	if r.onEvict != nil {
+		r.onEvict(key, value, reason)
+	}
+}
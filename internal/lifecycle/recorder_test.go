@@ -0,0 +1,45 @@
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("Removal is counted and passed to the callback", func(t *testing.T) {
+		type removed struct {
+			key    string
+			value  int
+			reason removal.Reason
+		}
+
+		var calls []removed
+		r := NewRecorder(func(key string, value int, reason removal.Reason) {
+			calls = append(calls, removed{key: key, value: value, reason: reason})
+		})
+
+		r.Hit()
+		r.Removal("a", 1, removal.Capacity)
+		r.Removal("b", 2, removal.Expired)
+
+		assert.Equal(t, []removed{
+			{key: "a", value: 1, reason: removal.Capacity},
+			{key: "b", value: 2, reason: removal.Expired},
+		}, calls)
+
+		snapshot := r.Snapshot(0)
+		assert.Equal(t, uint64(1), snapshot.Hits)
+		assert.Equal(t, uint64(1), snapshot.Evictions)
+		assert.Equal(t, uint64(1), snapshot.Expirations)
+	})
+
+	t.Run("Removal without callback is counted", func(t *testing.T) {
+		r := NewRecorder[string, int](nil)
+
+		r.Removal("a", 1, removal.Deleted)
+
+		assert.Equal(t, uint64(1), r.Snapshot(0).Deletions)
+	})
+}
@@ -0,0 +1,369 @@
+package lirscache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/list"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+// Cache is a LIRS cache. Entries are split by their inter-reference
+// recency, the number of other keys accessed between the last two accesses
+// of the entry. LIR entries have a low one and take 99% of the capacity,
+// HIR entries take the rest and are evicted first, in FIFO order of the
+// queue Q.
+//
+// The stack S keeps recently accessed entries in recency order, its bottom
+// is always the least recently used LIR entry. A HIR entry accessed again
+// while it is still in S has a lower recency than the bottom LIR entry, so
+// it becomes LIR and the bottom one becomes HIR. An evicted HIR entry stays
+// in S as a non-resident entry to detect its reuse.
+type Cache[K comparable, V any] struct {
+	// data holds both resident and non-resident entries.
+	data  map[K]*entry[K, V]
+	stack *list.List[*entry[K, V]]
+	queue *list.List[*entry[K, V]]
+	// nonResident keeps non-resident entries in eviction order to bound
+	// their number.
+	nonResident *list.List[*entry[K, V]]
+	expiry      *lifecycle.ExpiryQueue[*entry[K, V]]
+	capacity    int
+	lirCapacity int
+	lirCount    int
+	ttl         time.Duration
+	janitor     *janitor.Janitor
+	stats       *lifecycle.Recorder[K, V]
+	mu          sync.Mutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.Capacity <= 0 {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	cache := Cache[K, V]{
+		data:        make(map[K]*entry[K, V], params.Capacity),
+		stack:       list.New[*entry[K, V]](),
+		queue:       list.New[*entry[K, V]](),
+		nonResident: list.New[*entry[K, V]](),
+		expiry:      lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		capacity:    params.Capacity,
+		lirCapacity: params.Capacity - max(params.Capacity/100, 1),
+		ttl:         params.TTL,
+		stats:       lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok || !v.isResident() {
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	c.access(v)
+	c.stats.Hit()
+	return v.value, true
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok || !v.isResident() {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+// Len does not count remembered keys of evicted entries.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.residentLen()
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		if v.isResident() {
+			c.stats.Removal(v.key, v.value, removal.Deleted)
+		}
+	}
+
+	clear(c.data)
+	c.stack.Clear()
+	c.queue.Clear()
+	c.nonResident.Clear()
+	c.expiry.Clear()
+	c.lirCount = 0
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats.Snapshot(c.residentLen())
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && v.isResident() && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	switch {
+	case !ok:
+		c.addNewEntry(key, value, expiredAt)
+	case v.isResident():
+		c.updateEntry(v, value, expiredAt)
+	default:
+		c.restoreNonResident(v, value, expiredAt)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	c.access(entry)
+}
+
+// access moves the resident entry to the top of the stack. A HIR entry
+// which is still in the stack becomes LIR.
+func (c *Cache[K, V]) access(entry *entry[K, V]) {
+	switch {
+	case entry.status == lir:
+		c.stack.MoveToBack(entry.stackElement)
+		c.prune()
+	case c.stack.Contains(entry.stackElement):
+		c.stack.MoveToBack(entry.stackElement)
+		c.queue.Remove(entry.queueElement)
+		entry.queueElement = nil
+		c.makeLir(entry)
+	default:
+		entry.stackElement = c.stack.PushBack(entry)
+		c.queue.MoveToBack(entry.queueElement)
+		c.prune()
+	}
+}
+
+// addNewEntry stores a new entry as LIR until LIR entries fill their part
+// of the capacity, and as HIR afterwards.
+func (c *Cache[K, V]) addNewEntry(key K, value V, expiredAt time.Time) {
+	c.makeRoom()
+
+	entry := newEntry(key, value, expiredAt)
+	entry.stackElement = c.stack.PushBack(entry)
+	entry.expiry = c.expiry.Push(entry)
+	c.data[key] = entry
+
+	if c.lirCount < c.lirCapacity {
+		entry.status = lir
+		c.lirCount++
+		return
+	}
+
+	entry.status = hir
+	entry.queueElement = c.queue.PushBack(entry)
+	// A cache of capacity 1 has no LIR entries, so the stack keeps nothing.
+	c.prune()
+}
+
+// restoreNonResident stores the value of a non-resident entry. The key was
+// set again while it was still in the stack, so the entry becomes LIR.
+func (c *Cache[K, V]) restoreNonResident(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.nonResident.Remove(entry.queueElement)
+	entry.queueElement = nil
+
+	c.makeRoom()
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	entry.expiry = c.expiry.Push(entry)
+	c.stack.MoveToBack(entry.stackElement)
+	c.makeLir(entry)
+}
+
+// makeLir turns the entry into LIR. If there are too many LIR entries, the
+// one at the bottom of the stack becomes HIR.
+func (c *Cache[K, V]) makeLir(entry *entry[K, V]) {
+	entry.status = lir
+	c.lirCount++
+
+	if c.lirCount > c.lirCapacity {
+		bottom := c.stack.Front().Value
+		c.stack.Remove(bottom.stackElement)
+		bottom.stackElement = nil
+		bottom.status = hir
+		bottom.queueElement = c.queue.PushBack(bottom)
+		c.lirCount--
+	}
+
+	c.prune()
+}
+
+// makeRoom evicts the HIR entry at the front of Q if the cache is full. An
+// entry which is still in the stack stays there as a non-resident one.
+func (c *Cache[K, V]) makeRoom() {
+	if c.residentLen() < c.capacity {
+		return
+	}
+
+	victim := c.queue.Front().Value
+	c.queue.Remove(victim.queueElement)
+	c.expiry.Remove(victim.expiry)
+	c.stats.Removal(victim.key, victim.value, removal.Capacity)
+
+	if !c.stack.Contains(victim.stackElement) {
+		delete(c.data, victim.key)
+		return
+	}
+
+	victim.value = c.getZeroValue()
+	victim.status = nonResident
+	victim.queueElement = c.nonResident.PushBack(victim)
+
+	if c.nonResident.Len() > c.capacity {
+		c.removeNonResident(c.nonResident.Front().Value)
+	}
+}
+
+// prune removes HIR and non-resident entries from the bottom of the stack,
+// so that the bottom entry is LIR. Non-resident entries are forgotten.
+func (c *Cache[K, V]) prune() {
+	for e := c.stack.Front(); e != nil && e.Value.status != lir; e = c.stack.Front() {
+		entry := e.Value
+		if entry.status == nonResident {
+			c.removeNonResident(entry)
+			continue
+		}
+
+		c.stack.Remove(e)
+		entry.stackElement = nil
+	}
+}
+
+func (c *Cache[K, V]) residentLen() int {
+	return c.lirCount + c.queue.Len()
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	if entry.status == lir {
+		c.lirCount--
+	} else {
+		c.queue.Remove(entry.queueElement)
+	}
+
+	if c.stack.Contains(entry.stackElement) {
+		c.stack.Remove(entry.stackElement)
+	}
+
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+	c.prune()
+}
+
+func (c *Cache[K, V]) removeNonResident(entry *entry[K, V]) {
+	delete(c.data, entry.key)
+	c.stack.Remove(entry.stackElement)
+	c.nonResident.Remove(entry.queueElement)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
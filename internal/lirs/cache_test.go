@@ -0,0 +1,222 @@
+package lirscache
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	"github.com/conacry/inmem-cache/internal/list"
+	lrucache "github.com/conacry/inmem-cache/internal/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestCache_Fill_FirstValuesBecameLir() {
+	cache := s.newCache(3)
+
+	for i := range 3 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+
+	assert.Equal(s.T(), lir, cache.data["key0"].status)
+	assert.Equal(s.T(), lir, cache.data["key1"].status)
+	assert.Equal(s.T(), hir, cache.data["key2"].status)
+	assert.Equal(s.T(), []string{"key0", "key1", "key2"}, listKeys(cache.stack))
+	assert.Equal(s.T(), []string{"key2"}, listKeys(cache.queue))
+}
+
+func (s *CacheSuite) TestCache_NotEnoughCapacity_HirValueBecameNonResident() {
+	cache := s.newCache(3)
+
+	for i := range 4 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+
+	assert.Equal(s.T(), nonResident, cache.data["key2"].status)
+	assert.Zero(s.T(), cache.data["key2"].value)
+	assert.Equal(s.T(), []string{"key2"}, listKeys(cache.nonResident))
+	assert.Equal(s.T(), []string{"key3"}, listKeys(cache.queue))
+	assert.Equal(s.T(), 3, cache.Len())
+
+	_, exists := cache.Get("key2")
+	assert.False(s.T(), exists, "non-resident entry should not be returned")
+	assert.False(s.T(), cache.Delete("key2"))
+}
+
+func (s *CacheSuite) TestCache_SetNonResidentValue_ValueBecameLir() {
+	cache := s.newCache(3)
+
+	for i := range 4 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+
+	require.NoError(s.T(), cache.Set("key2", 20))
+
+	value, exists := cache.Get("key2")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 20, value)
+	assert.Equal(s.T(), lir, cache.data["key2"].status)
+	assert.Equal(s.T(), hir, cache.data["key0"].status, "the bottom LIR entry should become HIR")
+	assert.Equal(s.T(), []string{"key0"}, listKeys(cache.queue))
+	assert.Equal(s.T(), []string{"key1", "key3", "key2"}, listKeys(cache.stack))
+	assert.Equal(s.T(), 3, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_HitHirValueInStack_ValueBecameLir() {
+	cache := s.newCache(3)
+
+	for i := range 3 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+
+	cache.Get("key2")
+
+	assert.Equal(s.T(), lir, cache.data["key2"].status)
+	assert.Equal(s.T(), hir, cache.data["key0"].status)
+	assert.Equal(s.T(), []string{"key1", "key2"}, listKeys(cache.stack))
+	assert.Equal(s.T(), []string{"key0"}, listKeys(cache.queue))
+}
+
+func (s *CacheSuite) TestCache_HitBottomLirValue_StackWasPruned() {
+	cache := s.newCache(3)
+
+	for i := range 3 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+
+	cache.Get("key0")
+
+	assert.Equal(s.T(), []string{"key1", "key2", "key0"}, listKeys(cache.stack))
+	cache.Get("key1")
+	assert.Equal(s.T(), []string{"key0", "key1"}, listKeys(cache.stack), "the HIR entry at the bottom should be pruned")
+	assert.Equal(s.T(), []string{"key2"}, listKeys(cache.queue))
+}
+
+func (s *CacheSuite) TestCache_Scan_LirValuesSurvived() {
+	cache := s.newCache(10)
+
+	for i := range 9 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("hot%d", i), i))
+	}
+
+	for i := range 100 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("scan%d", i), i))
+	}
+
+	for i := range 9 {
+		value, exists := cache.Get(fmt.Sprintf("hot%d", i))
+		assert.True(s.T(), exists)
+		assert.Equal(s.T(), i, value)
+	}
+	assert.Equal(s.T(), 10, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_RandomOperations_StateIsConsistent() {
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	for capacity := 1; capacity <= 5; capacity++ {
+		cache := s.newCache(capacity)
+
+		for range 5_000 {
+			key := fmt.Sprintf("key%d", rnd.IntN(4*capacity))
+			switch rnd.IntN(4) {
+			case 0, 1:
+				require.NoError(s.T(), cache.Set(key, 0))
+			case 2:
+				cache.Get(key)
+			case 3:
+				cache.Delete(key)
+			}
+
+			s.assertConsistent(cache)
+		}
+	}
+}
+
+func (s *CacheSuite) newCache(capacity int) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+func (s *CacheSuite) assertConsistent(cache *Cache[string, int]) {
+	counts := map[status]int{}
+	for _, v := range cache.data {
+		counts[v.status]++
+		switch v.status {
+		case lir:
+			require.True(s.T(), cache.stack.Contains(v.stackElement))
+		case hir:
+			require.True(s.T(), cache.queue.Contains(v.queueElement))
+		case nonResident:
+			require.True(s.T(), cache.stack.Contains(v.stackElement))
+			require.True(s.T(), cache.nonResident.Contains(v.queueElement))
+		}
+	}
+
+	require.Equal(s.T(), counts[lir], cache.lirCount)
+	require.Equal(s.T(), counts[hir], cache.queue.Len())
+	require.Equal(s.T(), counts[nonResident], cache.nonResident.Len())
+	require.Equal(s.T(), cache.residentLen(), cache.expiry.Len())
+	require.LessOrEqual(s.T(), cache.lirCount, cache.lirCapacity)
+	require.LessOrEqual(s.T(), cache.residentLen(), cache.capacity)
+	require.LessOrEqual(s.T(), cache.nonResident.Len(), cache.capacity)
+
+	if front := cache.stack.Front(); front != nil {
+		require.Equal(s.T(), lir, front.Value.status, "the bottom of the stack should be LIR")
+	}
+}
+
+func listKeys(l *list.List[*entry[string, int]]) []string {
+	var keys []string
+	for e := l.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.key)
+	}
+
+	return keys
+}
+
+// BenchmarkCache_HitRatio replays the same traces against LIRS and LRU and
+// reports the hit ratio of each.
+func BenchmarkCache_HitRatio(b *testing.B) {
+	const capacity = 1_000
+
+	policies := []cachetest.Policy{
+		{Name: "lirs", NewCache: func() (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+		}},
+		{Name: "lru", NewCache: func() (cachetest.Cache, error) {
+			return lrucache.NewCache[string, int](lrucache.InitParam[string, int]{Capacity: capacity, TTL: time.Hour})
+		}},
+	}
+
+	cachetest.BenchmarkHitRatio(b, cachetest.DefaultTraces(), policies)
+}
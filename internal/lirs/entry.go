@@ -0,0 +1,53 @@
+package lirscache
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/list"
+)
+
+type status int
+
+const (
+	// lir entries have a low inter-reference recency and are never evicted
+	// while they stay LIR.
+	lir status = iota
+	// hir entries are resident entries with a high inter-reference recency,
+	// they are evicted first.
+	hir
+	// nonResident entries are evicted HIR entries which are still in the
+	// stack, only their keys are kept.
+	nonResident
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+	status    status
+	// stackElement links the entry into the stack S, it is nil if the
+	// entry is not in the stack.
+	stackElement *list.Element[*entry[K, V]]
+	// queueElement links a HIR entry into the queue Q and a non-resident
+	// entry into the queue of non-resident entries.
+	queueElement *list.Element[*entry[K, V]]
+	// expiry links a resident entry into the expiry queue.
+	expiry *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiredAt: expiredAt,
+	}
+}
+
+func (e *entry[K, V]) isResident() bool {
+	return e.status != nonResident
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
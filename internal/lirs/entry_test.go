@@ -0,0 +1,29 @@
+package lirscache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEntry(t *testing.T) {
+	t.Run("Create new entry", func(t *testing.T) {
+		expiredAt := time.Now().Add(10 * time.Second)
+		entry := newEntry("key", "value", expiredAt)
+
+		assert.Equal(t, "key", entry.key)
+		assert.Equal(t, "value", entry.value)
+		assert.Equal(t, expiredAt, entry.expiredAt)
+		assert.Nil(t, entry.stackElement)
+		assert.Nil(t, entry.queueElement)
+		assert.True(t, entry.isResident())
+	})
+
+	t.Run("Non-resident entry is not resident", func(t *testing.T) {
+		entry := newEntry("key", "value", time.Time{})
+		entry.status = nonResident
+
+		assert.False(t, entry.isResident())
+	})
+}
@@ -0,0 +1,150 @@
+package list
+
+// Element is an element of a List.
+type Element[T any] struct {
+	Value T
+	prev  *Element[T]
+	next  *Element[T]
+	list  *List[T]
+}
+
+// Next returns the element after e, or nil if e is the back of its list.
+func (e *Element[T]) Next() *Element[T] {
+	if e.list == nil || e.next == &e.list.root {
+		return nil
+	}
+
+	return e.next
+}
+
+// Prev returns the element before e, or nil if e is the front of its list.
+func (e *Element[T]) Prev() *Element[T] {
+	if e.list == nil || e.prev == &e.list.root {
+		return nil
+	}
+
+	return e.prev
+}
+
+// List is a doubly linked list used by eviction policies to keep entries in
+// access or insertion order. Every operation is O(1). Policies add new
+// elements to the back, so the front is the oldest element.
+type List[T any] struct {
+	root Element[T]
+	len  int
+}
+
+func New[T any]() *List[T] {
+	l := List[T]{}
+	l.root.next = &l.root
+	l.root.prev = &l.root
+
+	return &l
+}
+
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+// Front returns the first element of the list, or nil if the list is empty.
+func (l *List[T]) Front() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+
+	return l.root.next
+}
+
+// Back returns the last element of the list, or nil if the list is empty.
+func (l *List[T]) Back() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+
+	return l.root.prev
+}
+
+func (l *List[T]) PushBack(v T) *Element[T] {
+	e := &Element[T]{Value: v}
+	l.insertBefore(e, &l.root)
+
+	return e
+}
+
+func (l *List[T]) PushFront(v T) *Element[T] {
+	e := &Element[T]{Value: v}
+	l.insertBefore(e, l.root.next)
+
+	return e
+}
+
+// InsertBefore inserts v right before mark and returns the new element. The
+// mark must belong to the list.
+func (l *List[T]) InsertBefore(v T, mark *Element[T]) *Element[T] {
+	e := &Element[T]{Value: v}
+	l.insertBefore(e, mark)
+
+	return e
+}
+
+// MoveToBack moves e to the back of the list. It does nothing if e does not
+// belong to the list.
+func (l *List[T]) MoveToBack(e *Element[T]) {
+	if e.list != l || l.root.prev == e {
+		return
+	}
+
+	l.unlink(e)
+	l.insertBefore(e, &l.root)
+}
+
+// MoveToFront moves e to the front of the list. It does nothing if e does
+// not belong to the list.
+func (l *List[T]) MoveToFront(e *Element[T]) {
+	if e.list != l || l.root.next == e {
+		return
+	}
+
+	l.unlink(e)
+	l.insertBefore(e, l.root.next)
+}
+
+// Remove removes e from the list. It does nothing if e does not belong to
+// the list.
+func (l *List[T]) Remove(e *Element[T]) {
+	if e.list != l {
+		return
+	}
+
+	l.unlink(e)
+}
+
+// Contains reports whether e belongs to the list.
+func (l *List[T]) Contains(e *Element[T]) bool {
+	return e != nil && e.list == l
+}
+
+// Clear removes all elements.
+func (l *List[T]) Clear() {
+	for e := l.Front(); e != nil; e = l.Front() {
+		l.unlink(e)
+	}
+}
+
+func (l *List[T]) insertBefore(e, mark *Element[T]) {
+	e.prev = mark.prev
+	e.next = mark
+	e.list = l
+	mark.prev.next = e
+	mark.prev = e
+	l.len++
+}
+
+func (l *List[T]) unlink(e *Element[T]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+	e.next = nil
+	e.list = nil
+	l.len--
+}
@@ -0,0 +1,129 @@
+package list
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ListSuite struct {
+	suite.Suite
+}
+
+func TestListSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ListSuite))
+}
+
+func (s *ListSuite) TestNew_ReturnEmptyList() {
+	l := New[int]()
+	require.NotNil(s.T(), l)
+	assert.Equal(s.T(), 0, l.Len())
+	assert.Nil(s.T(), l.Front())
+	assert.Nil(s.T(), l.Back())
+}
+
+func (s *ListSuite) TestPushBackAndPushFront_ValuesWereAdded() {
+	l := New[int]()
+
+	l.PushBack(2)
+	l.PushBack(3)
+	l.PushFront(1)
+
+	assert.Equal(s.T(), []int{1, 2, 3}, values(l))
+	assert.Equal(s.T(), 3, l.Len())
+	assert.Equal(s.T(), 1, l.Front().Value)
+	assert.Equal(s.T(), 3, l.Back().Value)
+}
+
+func (s *ListSuite) TestInsertBefore_ValueWasInserted() {
+	l := New[int]()
+	e1 := l.PushBack(1)
+	e3 := l.PushBack(3)
+
+	l.InsertBefore(2, e3)
+	l.InsertBefore(0, e1)
+
+	assert.Equal(s.T(), []int{0, 1, 2, 3}, values(l))
+	assert.Equal(s.T(), 4, l.Len())
+}
+
+func (s *ListSuite) TestMove_ValuesWereReordered() {
+	l := New[int]()
+	e1 := l.PushBack(1)
+	l.PushBack(2)
+	e3 := l.PushBack(3)
+
+	l.MoveToBack(e1)
+	assert.Equal(s.T(), []int{2, 3, 1}, values(l))
+
+	l.MoveToFront(e3)
+	assert.Equal(s.T(), []int{3, 2, 1}, values(l))
+	assert.Equal(s.T(), 3, l.Len())
+}
+
+func (s *ListSuite) TestRemove_ValueWasRemoved() {
+	l := New[int]()
+	l.PushBack(1)
+	e2 := l.PushBack(2)
+	l.PushBack(3)
+
+	l.Remove(e2)
+	assert.Equal(s.T(), []int{1, 3}, values(l))
+	assert.Equal(s.T(), 2, l.Len())
+	assert.False(s.T(), l.Contains(e2))
+
+	l.Remove(e2)
+	assert.Equal(s.T(), 2, l.Len())
+}
+
+func (s *ListSuite) TestElementOfOtherList_NothingWasChanged() {
+	l := New[int]()
+	other := New[int]()
+	l.PushBack(1)
+	e := other.PushBack(2)
+
+	l.MoveToBack(e)
+	l.MoveToFront(e)
+	l.Remove(e)
+
+	assert.Equal(s.T(), []int{1}, values(l))
+	assert.Equal(s.T(), []int{2}, values(other))
+	assert.True(s.T(), other.Contains(e))
+	assert.False(s.T(), l.Contains(e))
+}
+
+func (s *ListSuite) TestPrev_ValuesWereIteratedBackwards() {
+	l := New[int]()
+	l.PushBack(1)
+	l.PushBack(2)
+	l.PushBack(3)
+
+	var got []int
+	for e := l.Back(); e != nil; e = e.Prev() {
+		got = append(got, e.Value)
+	}
+	assert.Equal(s.T(), []int{3, 2, 1}, got)
+}
+
+func (s *ListSuite) TestClear_ListIsEmpty() {
+	l := New[int]()
+	e := l.PushBack(1)
+	l.PushBack(2)
+
+	l.Clear()
+	assert.Equal(s.T(), 0, l.Len())
+	assert.Nil(s.T(), l.Front())
+	assert.False(s.T(), l.Contains(e))
+}
+
+func values(l *List[int]) []int {
+	var result []int
+	for e := l.Front(); e != nil; e = e.Next() {
+		result = append(result, e.Value)
+	}
+
+	return result
+}
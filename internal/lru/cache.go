@@ -3,82 +3,351 @@ package lrucache
 import (
 	"sync"
 	"time"
+
+	"github.com/conacry/inmem-cache/internal/cacheerr"
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/readbuf"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
 )
 
 type Cache[K comparable, V any] struct {
-	data     map[K]entry[V]
-	list     *ageList[K]
+	data     map[K]*entry[K, V]
+	list     *ageList[K, V]
+	expiry   *lifecycle.ExpiryQueue[*entry[K, V]]
 	capacity int
+	cost     int64
+	maxBytes int64
+	bytes    int64
+	sizer    func(key K, value V) int64
 	ttl      time.Duration
-	mu       sync.Mutex
+	janitor  *janitor.Janitor
+	stats    *lifecycle.Recorder[K, V]
+	reads    *readbuf.Buffer[entry[K, V]]
+	mu       sync.RWMutex
 }
 
-func NewCache[K comparable, V any](params InitParam) (*Cache[K, V], error) {
-	if params.Capacity <= 0 {
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.MaxBytes < 0 {
+		return nil, ErrIllegalMaxBytes
+	}
+
+	if params.Capacity < 0 || (params.Capacity == 0 && params.MaxBytes <= 0) {
 		return nil, ErrIllegalCapacity
 	}
 
+	if params.MaxBytes > 0 && params.Sizer == nil {
+		return nil, ErrSizerRequired
+	}
+
 	if params.TTL <= 0 {
 		return nil, ErrIllegalTTL
 	}
 
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
 	cache := Cache[K, V]{
-		data:     make(map[K]entry[V], params.Capacity),
-		list:     newAgeList[K](params.Capacity),
+		data:     make(map[K]*entry[K, V], params.Capacity),
+		list:     newAgeList[K, V](),
+		expiry:   lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
 		capacity: params.Capacity,
+		maxBytes: params.MaxBytes,
+		sizer:    params.Sizer,
 		ttl:      params.TTL,
+		stats:    lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.BufferedReads {
+		cache.reads = readbuf.New[entry[K, V]]()
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
 	}
 
 	return &cache, nil
 }
 
 func (c *Cache[K, V]) Get(key K) (V, bool) {
+	if c.reads != nil {
+		return c.getBuffered(key)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	v, ok := c.data[key]
 	if !ok {
+		c.stats.Miss()
 		return c.getZeroValue(), false
 	}
 
-	if !v.expiredAt.IsZero() && time.Now().After(v.expiredAt) {
-		delete(c.data, key)
-		c.list.Remove(key)
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		c.stats.Miss()
 		return c.getZeroValue(), false
 	}
 
-	c.list.MakeYoungest(key)
+	c.list.MakeYoungest(v)
+	c.stats.Hit()
 	return v.value, true
 }
 
+// getBuffered looks the entry up under the read lock and records the access
+// into the read buffer instead of updating the list, so concurrent hits
+// do not contend. The buffer is drained by whoever holds the write lock.
+func (c *Cache[K, V]) getBuffered(key K) (V, bool) {
+	c.mu.RLock()
+	v, ok := c.data[key]
+	if ok && !lifecycle.IsExpired(v.expiredAt) {
+		value := v.value
+		c.mu.RUnlock()
+
+		if c.reads.Add(v) && c.mu.TryLock() {
+			c.drainReads()
+			c.mu.Unlock()
+		}
+
+		c.stats.Hit()
+		return value, true
+	}
+	c.mu.RUnlock()
+
+	if ok {
+		c.removeExpired(key)
+	}
+
+	c.stats.Miss()
+	return c.getZeroValue(), false
+}
+
+// removeExpired removes the entry by the key if it is still expired once
+// the write lock is taken.
+func (c *Cache[K, V]) removeExpired(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+// drainReads applies buffered accesses. Entries removed after the access
+// was recorded are skipped.
+func (c *Cache[K, V]) drainReads() {
+	if c.reads == nil {
+		return
+	}
+
+	c.reads.Drain(func(v *entry[K, V]) {
+		if c.data[v.key] == v {
+			c.list.MakeYoungest(v)
+		}
+	})
+}
+
 func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, 1, time.Now().Add(c.ttl))
+}
+
+// SetWithCost stores the value with the given cost and the default TTL. The
+// capacity of the cache limits the total cost of entries, Set, SetWithTTL
+// and SetWithDeadline store entries with cost 1. Without the capacity the
+// cost would limit nothing, so ErrCostCapacityRequired is returned.
+func (c *Cache[K, V]) SetWithCost(key K, value V, cost int64) error {
+	if cost <= 0 {
+		return ErrIllegalCost
+	}
+
+	if c.capacity == 0 {
+		return ErrCostCapacityRequired
+	}
+
+	return c.set(key, value, cost, time.Now().Add(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, 1, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, 1, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.data)
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		c.stats.Removal(v.key, v.value, removal.Deleted)
+	}
+
+	clear(c.data)
+	c.list.Clear()
+	c.expiry.Clear()
+	c.cost = 0
+	c.bytes = 0
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stats.Snapshot(len(c.data))
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, cost int64, expiredAt time.Time) error {
+	if c.capacity > 0 && cost > int64(c.capacity) {
+		return &cacheerr.CostTooLargeError{Cost: cost, Capacity: int64(c.capacity)}
+	}
+
+	size := c.sizeOf(key, value)
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return ErrEntryTooLarge
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	item := newEntry(value, c.ttl)
-	if _, ok := c.data[key]; ok {
-		c.updateEntry(key, item)
+	c.drainReads()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	if ok {
+		c.updateEntry(v, value, cost, size, expiredAt)
 	} else {
-		c.addNewEntry(key, item)
+		c.addNewEntry(key, value, cost, size, expiredAt)
 	}
 
+	c.evictOverflow()
+	c.stats.Set()
+
 	return nil
 }
 
-func (c *Cache[K, V]) updateEntry(key K, entry entry[V]) {
-	c.list.MakeYoungest(key)
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, cost, size int64, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	c.cost += cost - entry.cost
+	c.bytes += size - entry.size
+	entry.value = value
+	entry.cost = cost
+	entry.size = size
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	c.list.MakeYoungest(entry)
+}
+
+func (c *Cache[K, V]) addNewEntry(key K, value V, cost, size int64, expiredAt time.Time) {
+	entry := newEntry(key, value, expiredAt)
+	entry.cost = cost
+	entry.size = size
+
 	c.data[key] = entry
+	c.cost += cost
+	c.bytes += size
+	c.list.Add(entry)
+	entry.expiry = c.expiry.Push(entry)
 }
 
-func (c *Cache[K, V]) addNewEntry(key K, entry entry[V]) {
-	if len(c.data) >= c.capacity {
-		keyToRemove := c.list.GetOldest()
-		c.list.Remove(keyToRemove)
-		delete(c.data, keyToRemove)
+// evictOverflow evicts the oldest entries until the cache fits both the
+// capacity and the max bytes limits. The entry set last is the youngest one,
+// so it is never evicted here.
+func (c *Cache[K, V]) evictOverflow() {
+	for c.isOverflowed() {
+		c.removeEntry(c.list.GetOldest(), removal.Capacity)
 	}
+}
 
-	c.data[key] = entry
-	c.list.Add(key)
+func (c *Cache[K, V]) isOverflowed() bool {
+	if c.capacity > 0 && c.cost > int64(c.capacity) {
+		return true
+	}
+
+	return c.maxBytes > 0 && c.bytes > c.maxBytes
+}
+
+func (c *Cache[K, V]) sizeOf(key K, value V) int64 {
+	if c.sizer == nil {
+		return 0
+	}
+
+	return c.sizer(key, value)
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.cost -= entry.cost
+	c.bytes -= entry.size
+	c.list.Remove(entry)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
 }
 
 func (c *Cache[K, T]) getZeroValue() T {
@@ -1,9 +1,14 @@
 package lrucache
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/conacry/inmem-cache/internal/cacheerr"
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -21,7 +26,7 @@ func TestCacheSuite(t *testing.T) {
 func (s *CacheSuite) TestNewCache_IllegalCapacity_ReturnError() {
 	illegalCapacity := 0
 
-	params := InitParam{Capacity: illegalCapacity}
+	params := InitParam[string, struct{}]{Capacity: illegalCapacity}
 	cache, err := NewCache[string, struct{}](params)
 	assert.Nil(s.T(), cache)
 	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
@@ -31,7 +36,7 @@ func (s *CacheSuite) TestNewCache_IllegalTTL_ReturnError() {
 	correctCapacity := 50
 	illegalTTL := 0 * time.Millisecond
 
-	params := InitParam{
+	params := InitParam[string, struct{}]{
 		Capacity: correctCapacity,
 		TTL:      illegalTTL,
 	}
@@ -44,7 +49,7 @@ func (s *CacheSuite) TestNewCache_CorrectInitParams_ReturnCache() {
 	correctCapacity := 50
 	correctTTL := 100 * time.Millisecond
 
-	params := InitParam{
+	params := InitParam[string, struct{}]{
 		Capacity: correctCapacity,
 		TTL:      correctTTL,
 	}
@@ -59,7 +64,7 @@ func (s *CacheSuite) TestCache_TtlIsNotExpired_CacheReturnedStoredValue() {
 		Field2 int
 	}
 
-	params := InitParam{
+	params := InitParam[string, StructForCache]{
 		Capacity: 1,
 		TTL:      100 * time.Millisecond,
 	}
@@ -87,7 +92,7 @@ func (s *CacheSuite) TestCache_TtlIsExpired_CacheWasNotReturnStoredValue() {
 		Field2 int
 	}
 
-	params := InitParam{
+	params := InitParam[string, StructForCache]{
 		Capacity: 1,
 		TTL:      100 * time.Millisecond,
 	}
@@ -117,7 +122,7 @@ func (s *CacheSuite) TestCache_NotEnoughCapacity_TheOldestValueWasEvicted() {
 		Field2 int
 	}
 
-	params := InitParam{
+	params := InitParam[string, StructForCache]{
 		Capacity: 1,
 		TTL:      100 * time.Millisecond,
 	}
@@ -160,7 +165,7 @@ func (s *CacheSuite) TestCache_UsedTheSameKey_ValueInCacheWasUpdated() {
 		Field2 int
 	}
 
-	params := InitParam{
+	params := InitParam[string, StructForCache]{
 		Capacity: 1,
 		TTL:      100 * time.Millisecond,
 	}
@@ -195,3 +200,639 @@ func (s *CacheSuite) TestCache_UsedTheSameKey_ValueInCacheWasUpdated() {
 	assert.True(s.T(), exists)
 	assert.Equal(s.T(), valueTwo, storedValueTwo)
 }
+
+func (s *CacheSuite) TestCache_DeleteExistedKey_ValueWasRemoved() {
+	params := InitParam[string, int]{
+		Capacity: 2,
+		TTL:      100 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	keyOne := "keyOne"
+	keyTwo := "keyTwo"
+	keyThree := "keyThree"
+
+	err = cache.Set(keyOne, 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set(keyTwo, 100501)
+	require.NoError(s.T(), err)
+
+	deleted := cache.Delete(keyOne)
+	assert.True(s.T(), deleted)
+	assert.Equal(s.T(), 1, cache.Len())
+
+	err = cache.Set(keyThree, 100502)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	_, exists := cache.Get(keyOne)
+	assert.False(s.T(), exists)
+
+	storedValueTwo, exists := cache.Get(keyTwo)
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 100501, storedValueTwo)
+
+	storedValueThree, exists := cache.Get(keyThree)
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 100502, storedValueThree)
+}
+
+func (s *CacheSuite) TestCache_DeleteNotExistedKey_ReturnFalse() {
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      100 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	deleted := cache.Delete("not_existed_key")
+	assert.False(s.T(), deleted)
+}
+
+func (s *CacheSuite) TestCache_Clear_AllValuesWereRemoved() {
+	params := InitParam[string, int]{
+		Capacity: 2,
+		TTL:      100 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("keyOne", 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set("keyTwo", 100501)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 2, cache.Len())
+
+	cache.Clear()
+	assert.Equal(s.T(), 0, cache.Len())
+
+	_, exists := cache.Get("keyOne")
+	assert.False(s.T(), exists)
+
+	err = cache.Set("keyThree", 100502)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, cache.Len())
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	for _, capacity := range []int{1_000, 10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("capacity=%d", capacity), func(b *testing.B) {
+			params := InitParam[int, int]{
+				Capacity: capacity,
+				TTL:      time.Hour,
+			}
+			cache, err := NewCache[int, int](params)
+			require.NoError(b, err)
+
+			for i := 0; i < capacity; i++ {
+				err = cache.Set(i, i)
+				require.NoError(b, err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cache.Get(i % capacity)
+			}
+		})
+	}
+}
+
+func BenchmarkCache_SetWithEviction(b *testing.B) {
+	for _, capacity := range []int{1_000, 10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("capacity=%d", capacity), func(b *testing.B) {
+			params := InitParam[int, int]{
+				Capacity: capacity,
+				TTL:      time.Hour,
+			}
+			cache, err := NewCache[int, int](params)
+			require.NoError(b, err)
+
+			for i := 0; i < capacity; i++ {
+				err = cache.Set(i, i)
+				require.NoError(b, err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = cache.Set(capacity+i, i)
+			}
+		})
+	}
+}
+
+func (s *CacheSuite) TestNewCache_IllegalCleanupInterval_ReturnError() {
+	params := InitParam[string, struct{}]{
+		Capacity:        50,
+		TTL:             100 * time.Millisecond,
+		CleanupInterval: -time.Second,
+	}
+	cache, err := NewCache[string, struct{}](params)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestCache_WithCleanupInterval_ExpiredValueWasRemovedWithoutAccess() {
+	params := InitParam[string, int]{
+		Capacity:        2,
+		TTL:             50 * time.Millisecond,
+		CleanupInterval: 10 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+	defer cache.Close()
+
+	err = cache.Set("keyOne", 100500)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, cache.Len())
+
+	assert.Eventually(s.T(), func() bool {
+		return cache.Len() == 0
+	}, time.Second, 10*time.Millisecond)
+
+	cache.mu.Lock()
+	assert.Equal(s.T(), 0, cache.list.Len())
+	cache.mu.Unlock()
+}
+
+func (s *CacheSuite) TestCache_CloseWithoutCleanupInterval_NotPanics() {
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      100 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	assert.NotPanics(s.T(), cache.Close)
+}
+
+func (s *CacheSuite) TestCache_SetWithTTL_ValueExpiredByOwnTTL() {
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	ttl := 50 * time.Millisecond
+	err = cache.SetWithTTL("key", 100500, ttl)
+	require.NoError(s.T(), err)
+
+	storedValue, exists := cache.Get("key")
+	require.True(s.T(), exists)
+	assert.Equal(s.T(), 100500, storedValue)
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	_, exists = cache.Get("key")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_SetWithZeroTTL_ValueNeverExpires() {
+	ttl := 50 * time.Millisecond
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      ttl,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithTTL("key", 100500, 0)
+	require.NoError(s.T(), err)
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	storedValue, exists := cache.Get("key")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 100500, storedValue)
+}
+
+func (s *CacheSuite) TestCache_SetWithNegativeTTL_ReturnError() {
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithTTL("key", 100500, -time.Second)
+	assert.ErrorIs(s.T(), err, ErrIllegalEntryTTL)
+	assert.Equal(s.T(), 0, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_SetWithDeadline_ValueExpiredAtDeadline() {
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	err = cache.SetWithDeadline("key", 100500, deadline)
+	require.NoError(s.T(), err)
+
+	storedValue, exists := cache.Get("key")
+	require.True(s.T(), exists)
+	assert.Equal(s.T(), 100500, storedValue)
+
+	time.Sleep(time.Until(deadline) + 20*time.Millisecond)
+
+	_, exists = cache.Get("key")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_SetWithPastDeadline_ReturnError() {
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithDeadline("key", 100500, time.Now().Add(-time.Second))
+	assert.ErrorIs(s.T(), err, ErrIllegalDeadline)
+	assert.Equal(s.T(), 0, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_WithOnEvict_CallbackReceivedRemovalReasons() {
+	recorder := &evictionRecorder{}
+	ttl := 50 * time.Millisecond
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      ttl,
+		OnEvict:  recorder.record,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set("key1", 100501)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 100502)
+	require.NoError(s.T(), err)
+	deleted := cache.Delete("key2")
+	require.True(s.T(), deleted)
+
+	err = cache.Set("key3", 100503)
+	require.NoError(s.T(), err)
+	time.Sleep(ttl + 20*time.Millisecond)
+	_, exists := cache.Get("key3")
+	require.False(s.T(), exists)
+
+	err = cache.Set("key4", 100504)
+	require.NoError(s.T(), err)
+	cache.Clear()
+
+	expected := []eviction{
+		{key: "key1", value: 100500, reason: removal.Replaced},
+		{key: "key1", value: 100501, reason: removal.Capacity},
+		{key: "key2", value: 100502, reason: removal.Deleted},
+		{key: "key3", value: 100503, reason: removal.Expired},
+		{key: "key4", value: 100504, reason: removal.Deleted},
+	}
+	assert.Equal(s.T(), expected, recorder.evictions)
+}
+
+type eviction struct {
+	key    string
+	value  int
+	reason removal.Reason
+}
+
+type evictionRecorder struct {
+	evictions []eviction
+}
+
+func (r *evictionRecorder) record(key string, value int, reason removal.Reason) {
+	r.evictions = append(r.evictions, eviction{key: key, value: value, reason: reason})
+}
+
+func (s *CacheSuite) TestCache_Stats_CountersWereCollected() {
+	ttl := 50 * time.Millisecond
+	params := InitParam[string, int]{
+		Capacity: 1,
+		TTL:      ttl,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	_, exists := cache.Get("key1")
+	require.True(s.T(), exists)
+	_, exists = cache.Get("not_existed_key")
+	require.False(s.T(), exists)
+
+	err = cache.Set("key2", 100501)
+	require.NoError(s.T(), err)
+	time.Sleep(ttl + 20*time.Millisecond)
+	_, exists = cache.Get("key2")
+	require.False(s.T(), exists)
+
+	expected := stats.Stats{
+		Hits:        1,
+		Misses:      2,
+		Sets:        2,
+		Evictions:   1,
+		Expirations: 1,
+		Size:        0,
+	}
+	assert.Equal(s.T(), expected, cache.Stats())
+	assert.InDelta(s.T(), 1.0/3.0, cache.Stats().HitRatio(), 0.001)
+
+	cache.ResetStats()
+	assert.Equal(s.T(), stats.Stats{}, cache.Stats())
+}
+
+func (s *CacheSuite) TestNewCache_IllegalMaxBytes_ReturnError() {
+	params := InitParam[string, string]{
+		TTL:      time.Minute,
+		MaxBytes: -1,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalMaxBytes)
+}
+
+func (s *CacheSuite) TestNewCache_MaxBytesWithoutSizer_ReturnError() {
+	params := InitParam[string, string]{
+		TTL:      time.Minute,
+		MaxBytes: 100,
+	}
+	cache, err := NewCache[string, string](params)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrSizerRequired)
+}
+
+func (s *CacheSuite) TestCache_NotEnoughBytes_ValuesWereEvictedUntilNewValueFits() {
+	params := InitParam[string, string]{
+		TTL:      time.Minute,
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "1234")
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", "1234")
+	require.NoError(s.T(), err)
+	err = cache.Set("key3", "12")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 3, cache.Len())
+
+	err = cache.Set("key4", "12345678")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists)
+	_, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+
+	value, exists := cache.Get("key3")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "12", value)
+
+	value, exists = cache.Get("key4")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "12345678", value)
+}
+
+func (s *CacheSuite) TestCache_UpdatedValueGrew_OtherValuesWereEvicted() {
+	params := InitParam[string, string]{
+		TTL:      time.Minute,
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "1234")
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", "1234")
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "123456789")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, cache.Len())
+
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "123456789", value)
+}
+
+func (s *CacheSuite) TestCache_ValueLargerThanMaxBytes_ReturnError() {
+	params := InitParam[string, string]{
+		TTL:      time.Minute,
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "1234")
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key2", "12345678901")
+	assert.ErrorIs(s.T(), err, ErrEntryTooLarge)
+	assert.Equal(s.T(), 1, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_SetWithCost_ValuesWereEvictedUntilCostFits() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", 1, 4)
+	require.NoError(s.T(), err)
+	err = cache.SetWithCost("key2", 2, 4)
+	require.NoError(s.T(), err)
+	err = cache.Set("key3", 3)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 3, cache.Len())
+
+	err = cache.SetWithCost("key4", 4, 8)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists)
+	_, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+
+	value, exists := cache.Get("key3")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 3, value)
+
+	value, exists = cache.Get("key4")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 4, value)
+}
+
+func (s *CacheSuite) TestCache_SetWithCostUpdatedCostGrew_OtherValuesWereEvicted() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", 1, 4)
+	require.NoError(s.T(), err)
+	err = cache.SetWithCost("key2", 2, 4)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", 10, 9)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, cache.Len())
+
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 10, value)
+}
+
+func (s *CacheSuite) TestCache_SetWithCostLargerThanCapacity_ReturnError() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key2", 2, 11)
+	var costErr *cacheerr.CostTooLargeError
+	require.ErrorAs(s.T(), err, &costErr)
+	assert.Equal(s.T(), int64(11), costErr.Cost)
+	assert.Equal(s.T(), int64(10), costErr.Capacity)
+	assert.Equal(s.T(), 1, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_SetWithCostWithoutCapacity_ReturnError() {
+	params := InitParam[string, string]{
+		MaxBytes: 100,
+		Sizer:    stringSizer,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", "value1", 5)
+	assert.ErrorIs(s.T(), err, ErrCostCapacityRequired)
+	assert.Zero(s.T(), cache.Len())
+}
+
+func (s *CacheSuite) TestCache_SetWithIllegalCost_ReturnError() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", 1, 0)
+	assert.ErrorIs(s.T(), err, ErrIllegalCost)
+	err = cache.SetWithCost("key1", 1, -1)
+	assert.ErrorIs(s.T(), err, ErrIllegalCost)
+	assert.Zero(s.T(), cache.Len())
+}
+
+func (s *CacheSuite) TestCache_DeleteWeightedValue_CostWasReleased() {
+	params := InitParam[string, int]{
+		Capacity: 10,
+		TTL:      time.Minute,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithCost("key1", 1, 8)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), cache.Delete("key1"))
+
+	err = cache.SetWithCost("key2", 2, 5)
+	require.NoError(s.T(), err)
+	err = cache.SetWithCost("key3", 3, 5)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+}
+
+func stringSizer(_ string, value string) int64 {
+	return int64(len(value))
+}
+
+func (s *CacheSuite) TestCache_BufferedReads_AccessWasAppliedBeforeEviction() {
+	params := InitParam[string, int]{
+		Capacity:      2,
+		TTL:           time.Hour,
+		BufferedReads: true,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 2)
+	require.NoError(s.T(), err)
+
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 1, value)
+
+	err = cache.Set("key3", 3)
+	require.NoError(s.T(), err)
+
+	_, exists = cache.Get("key1")
+	assert.True(s.T(), exists)
+	_, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+	_, exists = cache.Get("key3")
+	assert.True(s.T(), exists)
+}
+
+// BenchmarkCache_BufferedReads compares the default and the buffered read
+// path, see cachetest.BenchmarkBufferedReads.
+func BenchmarkCache_BufferedReads(b *testing.B) {
+	cachetest.BenchmarkBufferedReads(b,
+		func(capacity int, buffered bool) (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{
+				Capacity:      capacity,
+				TTL:           time.Hour,
+				BufferedReads: buffered,
+			})
+		},
+		func(cache cachetest.Cache) uint64 {
+			return cache.(*Cache[string, int]).reads.Dropped()
+		},
+	)
+}
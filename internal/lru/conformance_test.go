@@ -0,0 +1,36 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestConformanceSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &cachetest.Suite{
+		NewCache: func(params cachetest.Params) (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{
+				Capacity:        params.Capacity,
+				TTL:             params.TTL,
+				CleanupInterval: params.CleanupInterval,
+				OnEvict:         params.OnEvict,
+			})
+		},
+	})
+}
+
+func TestBufferedReadsSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &cachetest.BufferedReadsSuite{
+		NewCache: func(capacity int) (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{
+				Capacity:      capacity,
+				TTL:           time.Hour,
+				BufferedReads: true,
+			})
+		},
+	})
+}
@@ -2,16 +2,29 @@ package lrucache
 
 import (
 	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
 )
 
-type entry[T any] struct {
-	value     T
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
 	expiredAt time.Time
+	cost      int64
+	size      int64
+	prev      *entry[K, V]
+	next      *entry[K, V]
+	expiry    *heap.Element[*entry[K, V]]
 }
 
-func newEntry[T any](value T, ttl time.Duration) entry[T] {
-	return entry[T]{
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
 		value:     value,
-		expiredAt: time.Now().Add(ttl),
+		expiredAt: expiredAt,
 	}
 }
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
@@ -2,9 +2,20 @@ package lrucache
 
 import (
 	"errors"
+
+	"github.com/conacry/inmem-cache/internal/cacheerr"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
 )
 
 var (
-	ErrIllegalCapacity = errors.New("capacity should be greater than 0")
-	ErrIllegalTTL      = errors.New("ttl should be greater than 0")
+	ErrIllegalCapacity        = errors.New("capacity should be greater than 0 unless max bytes is set")
+	ErrIllegalTTL             = errors.New("ttl should be greater than 0")
+	ErrIllegalCleanupInterval = errors.New("cleanup interval should not be negative")
+	ErrIllegalEntryTTL        = lifecycle.ErrIllegalEntryTTL
+	ErrIllegalDeadline        = lifecycle.ErrIllegalDeadline
+	ErrIllegalMaxBytes        = errors.New("max bytes should not be negative")
+	ErrSizerRequired          = errors.New("sizer is required when max bytes is set")
+	ErrEntryTooLarge          = errors.New("entry size exceeds max bytes")
+	ErrIllegalCost            = errors.New("cost should be greater than 0")
+	ErrCostCapacityRequired   = cacheerr.ErrCostCapacityRequired
 )
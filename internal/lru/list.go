@@ -1,55 +1,72 @@
 package lrucache
 
-type ageList[V comparable] struct {
-	data []V
+// ageList is an intrusive doubly linked list of cache entries ordered from
+// the oldest to the youngest one. The list links entries through their own
+// prev/next pointers, so every operation is O(1).
+type ageList[K comparable, V any] struct {
+	root entry[K, V]
+	len  int
 }
 
-func newAgeList[V comparable](capacity int) *ageList[V] {
-	list := ageList[V]{
-		data: make([]V, 0, capacity),
-	}
+func newAgeList[K comparable, V any]() *ageList[K, V] {
+	list := ageList[K, V]{}
+	list.root.next = &list.root
+	list.root.prev = &list.root
 
 	return &list
 }
 
-func (q *ageList[V]) Add(value V) {
-	q.data = append(q.data, value)
-	return
+func (l *ageList[K, V]) Len() int {
+	return l.len
+}
+
+func (l *ageList[K, V]) Add(e *entry[K, V]) {
+	l.insertBefore(e, &l.root)
 }
 
-func (q *ageList[V]) GetOldest() V {
-	if len(q.data) == 0 {
-		var zeroValue V
-		return zeroValue
+func (l *ageList[K, V]) GetOldest() *entry[K, V] {
+	if l.len == 0 {
+		return nil
 	}
 
-	return q.data[0]
+	return l.root.next
 }
 
-func (q *ageList[V]) MakeYoungest(value V) {
-	q.Remove(value)
-	q.data = append(q.data, value)
-}
+func (l *ageList[K, V]) MakeYoungest(e *entry[K, V]) {
+	if l.root.prev == e {
+		return
+	}
 
-func (q *ageList[V]) Remove(value V) {
-	valueIndex := q.getIndex(value)
+	l.unlink(e)
+	l.insertBefore(e, &l.root)
+}
 
-	if valueIndex == -1 {
+func (l *ageList[K, V]) Remove(e *entry[K, V]) {
+	if e.prev == nil || e.next == nil {
 		return
 	}
 
-	q.data = append(q.data[:valueIndex], q.data[valueIndex+1:]...)
+	l.unlink(e)
+	e.prev = nil
+	e.next = nil
 }
 
-func (q *ageList[V]) getIndex(value V) int {
-	valueIndex := -1
+func (l *ageList[K, V]) Clear() {
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	l.len = 0
+}
 
-	for i, v := range q.data {
-		if v == value {
-			valueIndex = i
-			break
-		}
-	}
+func (l *ageList[K, V]) insertBefore(e, mark *entry[K, V]) {
+	e.prev = mark.prev
+	e.next = mark
+	mark.prev.next = e
+	mark.prev = e
+	l.len++
+}
 
-	return valueIndex
+func (l *ageList[K, V]) unlink(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	l.len--
 }
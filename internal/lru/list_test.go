@@ -17,64 +17,97 @@ func TestAgeListSuite(t *testing.T) {
 	suite.Run(t, new(AgeListSuite))
 }
 
-func (s *AgeListSuite) TestNewQueue_ReturnAgeList() {
-	correctCapacity := 50
-	q := newAgeList[int](correctCapacity)
-	require.NotNil(s.T(), q)
-	assert.Equal(s.T(), correctCapacity, cap(q.data))
+func (s *AgeListSuite) TestNewAgeList_ReturnEmptyAgeList() {
+	l := newAgeList[int, struct{}]()
+	require.NotNil(s.T(), l)
+	assert.Equal(s.T(), 0, l.Len())
+	assert.Nil(s.T(), l.GetOldest())
 }
 
 func (s *AgeListSuite) TestAdd_ValueWasAdded() {
-	q := newAgeList[int](50)
-	require.NotNil(s.T(), q)
+	l := newAgeList[int, struct{}]()
+	require.NotNil(s.T(), l)
 
-	q.Add(100500)
-	assert.Equal(s.T(), []int{100500}, q.data)
+	l.Add(newTestEntry(100500))
+	assert.Equal(s.T(), []int{100500}, listKeys(l))
+	assert.Equal(s.T(), 1, l.Len())
 }
 
 func (s *AgeListSuite) TestRemoveTheOldestValue_ListContainsValues_TheOldestValueWasRemoved() {
-	q := newAgeList[int](50)
-	require.NotNil(s.T(), q)
+	l := newAgeList[int, struct{}]()
+	require.NotNil(s.T(), l)
 
-	q.Add(100500)
-	q.Add(100501)
-	q.Add(100502)
+	l.Add(newTestEntry(100500))
+	l.Add(newTestEntry(100501))
+	l.Add(newTestEntry(100502))
 
-	keyToRemove := q.GetOldest()
-	q.Remove(keyToRemove)
-	assert.Equal(s.T(), []int{100501, 100502}, q.data)
+	entryToRemove := l.GetOldest()
+	require.NotNil(s.T(), entryToRemove)
+	assert.Equal(s.T(), 100500, entryToRemove.key)
+
+	l.Remove(entryToRemove)
+	assert.Equal(s.T(), []int{100501, 100502}, listKeys(l))
+	assert.Equal(s.T(), 2, l.Len())
+}
+
+func (s *AgeListSuite) TestMakeValueYoungest_ValueWasMadeYoungest() {
+	l := newAgeList[int, struct{}]()
+	require.NotNil(s.T(), l)
+
+	entry := newTestEntry(100501)
+	l.Add(newTestEntry(100500))
+	l.Add(entry)
+	l.Add(newTestEntry(100502))
+
+	l.MakeYoungest(entry)
+	assert.Equal(s.T(), []int{100500, 100502, 100501}, listKeys(l))
+
+	l.MakeYoungest(entry)
+	assert.Equal(s.T(), []int{100500, 100502, 100501}, listKeys(l))
+	assert.Equal(s.T(), 3, l.Len())
 }
 
-func (s *AgeListSuite) TestRemoveTheOldestValue_ListIsEmpty_ReturnDefaultValue() {
-	q := newAgeList[int](50)
-	require.NotNil(s.T(), q)
+func (s *AgeListSuite) TestRemove_ValueWasRemoved() {
+	l := newAgeList[int, struct{}]()
+	require.NotNil(s.T(), l)
 
-	keyToRemove := q.GetOldest()
-	require.Equal(s.T(), 0, keyToRemove)
+	entry := newTestEntry(100501)
+	l.Add(newTestEntry(100500))
+	l.Add(entry)
+	l.Add(newTestEntry(100502))
+
+	l.Remove(entry)
+	assert.Equal(s.T(), []int{100500, 100502}, listKeys(l))
+	assert.Nil(s.T(), entry.prev)
+	assert.Nil(s.T(), entry.next)
 
 	assert.NotPanics(s.T(), func() {
-		q.Remove(keyToRemove)
+		l.Remove(entry)
 	})
+	assert.Equal(s.T(), 2, l.Len())
 }
 
-func (s *AgeListSuite) TestMakeValueYoungest_ValueWasMadeYoungest() {
-	q := newAgeList[int](50)
-	require.NotNil(s.T(), q)
-
-	q.Add(100500)
-	q.Add(100501)
-	q.Add(100502)
-	q.MakeYoungest(100501)
-	assert.Equal(s.T(), []int{100500, 100502, 100501}, q.data)
+func (s *AgeListSuite) TestClear_ListIsEmpty() {
+	l := newAgeList[int, struct{}]()
+	require.NotNil(s.T(), l)
+
+	l.Add(newTestEntry(100500))
+	l.Add(newTestEntry(100501))
+	l.Clear()
+	assert.Empty(s.T(), listKeys(l))
+	assert.Equal(s.T(), 0, l.Len())
+	assert.Nil(s.T(), l.GetOldest())
 }
 
-func (s *AgeListSuite) TestRemove_ValueWasRemoved() {
-	q := newAgeList[int](50)
-	require.NotNil(s.T(), q)
-
-	q.Add(100500)
-	q.Add(100501)
-	q.Add(100502)
-	q.Remove(100501)
-	assert.Equal(s.T(), []int{100500, 100502}, q.data)
+func newTestEntry(key int) *entry[int, struct{}] {
+	return &entry[int, struct{}]{key: key}
+}
+
+func listKeys[K comparable, V any](l *ageList[K, V]) []K {
+	keys := make([]K, 0, l.Len())
+	for e := l.root.next; e != &l.root; e = e.next {
+		keys = append(keys, e.key)
+	}
+
+	return keys
 }
@@ -2,9 +2,25 @@ package lrucache
 
 import (
 	"time"
+
+	"github.com/conacry/inmem-cache/internal/removal"
 )
 
-type InitParam struct {
+type InitParam[K comparable, V any] struct {
+	// Capacity limits the total cost of entries, which is the number of
+	// entries unless SetWithCost is used. 0 means the cache is limited by
+	// MaxBytes only.
 	Capacity int
-	TTL      time.Duration
+	// MaxBytes limits the total size of entries measured by Sizer, 0 means
+	// there is no limit.
+	MaxBytes        int64
+	Sizer           func(key K, value V) int64
+	TTL             time.Duration
+	CleanupInterval time.Duration
+	// OnEvict is called for every entry leaving the cache. It runs while
+	// the cache lock is held, so it must not call the cache.
+	OnEvict func(key K, value V, reason removal.Reason)
+	// BufferedReads makes Get record accesses into a lossy read buffer
+	// instead of updating the eviction order under the write lock.
+	BufferedReads bool
 }
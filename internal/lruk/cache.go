@@ -0,0 +1,274 @@
+package lrukcache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+// Cache is an LRU-K cache. It remembers the times of the last K accesses of
+// every entry and evicts the entry with the largest backward K-distance,
+// which is the one whose K-th last access is the oldest. Entries accessed
+// fewer than K times are evicted first, in LRU order. The accesses of
+// evicted keys are kept in a bounded history table, so a key set again soon
+// after its eviction is not treated as a new one.
+//
+// Time is a logical clock advanced by every access.
+type Cache[K comparable, V any] struct {
+	data     map[K]*entry[K, V]
+	queue    *heap.Heap[*entry[K, V]]
+	expiry   *lifecycle.ExpiryQueue[*entry[K, V]]
+	history  *historyTable[K]
+	capacity int
+	k        int
+	clock    uint64
+	ttl      time.Duration
+	janitor  *janitor.Janitor
+	stats    *lifecycle.Recorder[K, V]
+	mu       sync.Mutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.Capacity <= 0 {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.K < 0 {
+		return nil, ErrIllegalK
+	}
+
+	if params.HistorySize < 0 {
+		return nil, ErrIllegalHistorySize
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	k := params.K
+	if k == 0 {
+		k = DefaultK
+	}
+
+	historySize := params.HistorySize
+	if historySize == 0 {
+		historySize = params.Capacity
+	}
+
+	cache := Cache[K, V]{
+		data:     make(map[K]*entry[K, V], params.Capacity),
+		queue:    heap.New((*entry[K, V]).evictsBefore),
+		expiry:   lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		history:  newHistoryTable[K](historySize),
+		capacity: params.Capacity,
+		k:        k,
+		ttl:      params.TTL,
+		stats:    lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	c.access(v)
+	c.stats.Hit()
+	return v.value, true
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.data)
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		c.stats.Removal(v.key, v.value, removal.Deleted)
+	}
+
+	clear(c.data)
+	c.queue.Clear()
+	c.expiry.Clear()
+	c.history.Clear()
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats.Snapshot(len(c.data))
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	if ok {
+		c.updateEntry(v, value, expiredAt)
+	} else {
+		c.addNewEntry(key, value, expiredAt)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	c.access(entry)
+}
+
+// addNewEntry stores a new entry. If the key was evicted recently, the
+// entry continues its remembered history.
+func (c *Cache[K, V]) addNewEntry(key K, value V, expiredAt time.Time) {
+	entry := newEntry(key, value, expiredAt)
+	// The history is taken before the eviction, which may push it out of
+	// the table.
+	if history, ok := c.history.Take(key); ok {
+		entry.history = history
+	}
+
+	if len(c.data) >= c.capacity {
+		c.evict()
+	}
+
+	entry.access(c.tick(), c.k)
+	entry.element = c.queue.Push(entry)
+	entry.expiry = c.expiry.Push(entry)
+	c.data[key] = entry
+}
+
+// evict removes the entry with the largest backward K-distance and
+// remembers its accesses in the history table.
+func (c *Cache[K, V]) evict() {
+	victim := c.queue.Peek().Value
+	c.removeEntry(victim, removal.Capacity)
+	c.history.Add(victim.key, victim.history)
+}
+
+func (c *Cache[K, V]) access(entry *entry[K, V]) {
+	entry.access(c.tick(), c.k)
+	c.queue.Fix(entry.element)
+}
+
+func (c *Cache[K, V]) tick() uint64 {
+	c.clock++
+	return c.clock
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.queue.Remove(entry.element)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
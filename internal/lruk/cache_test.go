@@ -0,0 +1,174 @@
+package lrukcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	lrucache "github.com/conacry/inmem-cache/internal/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, K: -1})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalK)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, HistorySize: -1})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalHistorySize)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestNewCache_DefaultParams_DefaultsWereSet() {
+	cache := s.newCache(10, 0)
+
+	assert.Equal(s.T(), DefaultK, cache.k)
+	assert.Equal(s.T(), 10, cache.history.size)
+}
+
+func (s *CacheSuite) TestCache_ValueAccessedOnce_ValueWasEvictedFirst() {
+	cache := s.newCache(2, 2)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	cache.Get("key1")
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	require.NoError(s.T(), cache.Set("key3", 3))
+
+	_, exists := cache.Get("key1")
+	assert.True(s.T(), exists, "the value accessed twice should be kept")
+	_, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_ValuesAccessedKTimes_ValueWithOldestKthAccessWasEvicted() {
+	cache := s.newCache(2, 2)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	cache.Get("key2")
+	cache.Get("key1")
+
+	require.NoError(s.T(), cache.Set("key3", 3))
+
+	assert.Equal(s.T(), 2, cache.Len())
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists, "key1 has the oldest second last access even though it was used last")
+	_, exists = cache.Get("key2")
+	assert.True(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_EvictedKeySetAgain_HistoryWasRestored() {
+	cache := s.newCache(1, 2)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	require.Equal(s.T(), 1, cache.history.Len())
+
+	require.NoError(s.T(), cache.Set("key1", 10))
+
+	assert.Equal(s.T(), []uint64{3, 1}, cache.data["key1"].history)
+	assert.Equal(s.T(), uint64(1), cache.data["key1"].kthAccess)
+	assert.Equal(s.T(), 1, cache.history.Len(), "key2 should be remembered instead")
+}
+
+func (s *CacheSuite) TestCache_ManyEvictions_HistoryWasBounded() {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: 10, HistorySize: 5})
+	require.NoError(s.T(), err)
+
+	for i := range 1000 {
+		key := fmt.Sprintf("key%d", i%50)
+		require.NoError(s.T(), cache.Set(key, i))
+		if i%3 == 0 {
+			cache.Get(key)
+		}
+
+		require.LessOrEqual(s.T(), cache.Len(), 10)
+		require.LessOrEqual(s.T(), cache.history.Len(), 5)
+		require.Equal(s.T(), len(cache.data), cache.queue.Len())
+	}
+}
+
+func (s *CacheSuite) TestCache_KIsOne_LeastRecentlyUsedValueWasEvicted() {
+	cache := s.newCache(2, 1)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	cache.Get("key1")
+
+	require.NoError(s.T(), cache.Set("key3", 3))
+
+	_, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	_, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_Scan_FrequentValuesSurvived() {
+	cache := s.newCache(10, 2)
+
+	for i := range 5 {
+		key := fmt.Sprintf("hot%d", i)
+		require.NoError(s.T(), cache.Set(key, i))
+		cache.Get(key)
+	}
+
+	for i := range 100 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("scan%d", i), i))
+	}
+
+	for i := range 5 {
+		value, exists := cache.Get(fmt.Sprintf("hot%d", i))
+		assert.True(s.T(), exists)
+		assert.Equal(s.T(), i, value)
+	}
+}
+
+func (s *CacheSuite) newCache(capacity int, k int) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: capacity, K: k})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+// BenchmarkCache_HitRatio replays the same traces against LRU-2 and LRU and
+// reports the hit ratio of each.
+func BenchmarkCache_HitRatio(b *testing.B) {
+	const capacity = 1_000
+
+	policies := []cachetest.Policy{
+		{Name: "lru-2", NewCache: func() (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+		}},
+		{Name: "lru", NewCache: func() (cachetest.Cache, error) {
+			return lrucache.NewCache[string, int](lrucache.InitParam[string, int]{Capacity: capacity, TTL: time.Hour})
+		}},
+	}
+
+	cachetest.BenchmarkHitRatio(b, cachetest.DefaultTraces(), policies)
+}
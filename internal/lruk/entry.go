@@ -0,0 +1,59 @@
+package lrukcache
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+	// history holds the logical times of the last K accesses, the most
+	// recent first.
+	history []uint64
+	// kthAccess is the time of the K-th last access, or 0 if the entry was
+	// accessed fewer than K times.
+	kthAccess uint64
+	element   *heap.Element[*entry[K, V]]
+	expiry    *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiredAt: expiredAt,
+	}
+}
+
+// access records an access at the logical time now. Only the last k
+// accesses are kept.
+func (e *entry[K, V]) access(now uint64, k int) {
+	if len(e.history) < k {
+		e.history = append(e.history, 0)
+	}
+
+	copy(e.history[1:], e.history)
+	e.history[0] = now
+
+	if len(e.history) == k {
+		e.kthAccess = e.history[k-1]
+	}
+}
+
+// evictsBefore reports whether e has a larger backward K-distance than
+// other. Entries accessed fewer than K times have an infinite distance and
+// are ordered by their last access.
+func (e *entry[K, V]) evictsBefore(other *entry[K, V]) bool {
+	if e.kthAccess != other.kthAccess {
+		return e.kthAccess < other.kthAccess
+	}
+
+	return e.history[0] < other.history[0]
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
@@ -0,0 +1,61 @@
+package lrukcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEntry(t *testing.T) {
+	t.Run("Create new entry", func(t *testing.T) {
+		expiredAt := time.Now().Add(10 * time.Second)
+		entry := newEntry("key", "value", expiredAt)
+
+		assert.Equal(t, "key", entry.key)
+		assert.Equal(t, "value", entry.value)
+		assert.Equal(t, expiredAt, entry.expiredAt)
+		assert.Empty(t, entry.history)
+		assert.Zero(t, entry.kthAccess)
+		assert.Nil(t, entry.element)
+	})
+}
+
+func TestEntry_Access(t *testing.T) {
+	t.Run("Last k accesses are kept", func(t *testing.T) {
+		entry := newEntry("key", "value", time.Time{})
+
+		entry.access(1, 3)
+		entry.access(2, 3)
+		assert.Equal(t, []uint64{2, 1}, entry.history)
+		assert.Zero(t, entry.kthAccess)
+
+		entry.access(3, 3)
+		entry.access(4, 3)
+		assert.Equal(t, []uint64{4, 3, 2}, entry.history)
+		assert.Equal(t, uint64(2), entry.kthAccess)
+	})
+
+	t.Run("Entry with older k-th access is evicted first", func(t *testing.T) {
+		older := newEntry("older", 1, time.Time{})
+		older.access(1, 2)
+		older.access(4, 2)
+
+		newer := newEntry("newer", 2, time.Time{})
+		newer.access(2, 2)
+		newer.access(3, 2)
+
+		assert.True(t, older.evictsBefore(newer))
+		assert.False(t, newer.evictsBefore(older))
+	})
+
+	t.Run("Entries accessed fewer than k times are ordered by last access", func(t *testing.T) {
+		first := newEntry("first", 1, time.Time{})
+		first.access(1, 2)
+
+		second := newEntry("second", 2, time.Time{})
+		second.access(2, 2)
+
+		assert.True(t, first.evictsBefore(second))
+	})
+}
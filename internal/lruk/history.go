@@ -0,0 +1,64 @@
+package lrukcache
+
+import (
+	"github.com/conacry/inmem-cache/internal/list"
+)
+
+// historyTable remembers the accesses of evicted keys, so that a key set
+// again soon after its eviction keeps its backward K-distance. The oldest
+// records are forgotten once the table is full.
+type historyTable[K comparable] struct {
+	records map[K]*list.Element[historyRecord[K]]
+	order   *list.List[historyRecord[K]]
+	size    int
+}
+
+type historyRecord[K comparable] struct {
+	key     K
+	history []uint64
+}
+
+func newHistoryTable[K comparable](size int) *historyTable[K] {
+	return &historyTable[K]{
+		records: make(map[K]*list.Element[historyRecord[K]], size),
+		order:   list.New[historyRecord[K]](),
+		size:    size,
+	}
+}
+
+func (t *historyTable[K]) Len() int {
+	return t.order.Len()
+}
+
+// Add remembers the accesses of the key and forgets the oldest records
+// beyond the size of the table.
+func (t *historyTable[K]) Add(key K, history []uint64) {
+	if element, ok := t.records[key]; ok {
+		t.order.Remove(element)
+	}
+
+	t.records[key] = t.order.PushBack(historyRecord[K]{key: key, history: history})
+
+	for t.order.Len() > t.size {
+		oldest := t.order.Front()
+		t.order.Remove(oldest)
+		delete(t.records, oldest.Value.key)
+	}
+}
+
+// Take returns the remembered accesses of the key and forgets them.
+func (t *historyTable[K]) Take(key K) ([]uint64, bool) {
+	element, ok := t.records[key]
+	if !ok {
+		return nil, false
+	}
+
+	t.order.Remove(element)
+	delete(t.records, key)
+	return element.Value.history, true
+}
+
+func (t *historyTable[K]) Clear() {
+	clear(t.records)
+	t.order.Clear()
+}
@@ -0,0 +1,59 @@
+package lrukcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryTable(t *testing.T) {
+	t.Run("Added history is taken once", func(t *testing.T) {
+		table := newHistoryTable[string](10)
+		table.Add("key1", []uint64{2, 1})
+
+		assert.Equal(t, 1, table.Len())
+
+		history, ok := table.Take("key1")
+		assert.True(t, ok)
+		assert.Equal(t, []uint64{2, 1}, history)
+
+		_, ok = table.Take("key1")
+		assert.False(t, ok)
+		assert.Zero(t, table.Len())
+	})
+
+	t.Run("Oldest records beyond size are forgotten", func(t *testing.T) {
+		table := newHistoryTable[string](2)
+		table.Add("key1", []uint64{1})
+		table.Add("key2", []uint64{2})
+		table.Add("key3", []uint64{3})
+
+		assert.Equal(t, 2, table.Len())
+		_, ok := table.Take("key1")
+		assert.False(t, ok)
+		_, ok = table.Take("key3")
+		assert.True(t, ok)
+	})
+
+	t.Run("Added again key replaces its record", func(t *testing.T) {
+		table := newHistoryTable[string](10)
+		table.Add("key1", []uint64{1})
+		table.Add("key1", []uint64{3, 1})
+
+		assert.Equal(t, 1, table.Len())
+		history, _ := table.Take("key1")
+		assert.Equal(t, []uint64{3, 1}, history)
+	})
+
+	t.Run("Clear forgets all records", func(t *testing.T) {
+		table := newHistoryTable[string](10)
+		table.Add("key1", []uint64{1})
+		table.Add("key2", []uint64{2})
+
+		table.Clear()
+
+		assert.Zero(t, table.Len())
+		_, ok := table.Take("key1")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,28 @@
+package lrukcache
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/removal"
+)
+
+// DefaultK is the number of remembered accesses when InitParam.K is 0.
+const DefaultK = 2
+
+type InitParam[K comparable, V any] struct {
+	Capacity int
+	// K is the number of last accesses remembered for every key. The entry
+	// whose K-th last access is the oldest is evicted first. 0 means
+	// DefaultK.
+	K int
+	// HistorySize limits the number of evicted keys whose accesses are
+	// remembered. 0 means Capacity.
+	HistorySize int
+	// TTL is the default time to live of entries, 0 means entries never
+	// expire.
+	TTL             time.Duration
+	CleanupInterval time.Duration
+	// OnEvict is called for every entry leaving the cache. It runs while
+	// the cache lock is held, so it must not call the cache.
+	OnEvict func(key K, value V, reason removal.Reason)
+}
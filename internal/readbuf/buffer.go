@@ -0,0 +1,92 @@
+package readbuf
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+const (
+	stripeSize = 16
+	stripeMask = stripeSize - 1
+	maxStripes = 64
+)
+
+// Buffer records reads of cache entries without taking the cache lock. The
+// reads are spread over striped ring buffers, which are drained in batches
+// by the owner of the cache lock.
+//
+// The buffer is lossy: a read is dropped when its stripe is full or another
+// goroutine is writing to the same slot. A dropped read only makes the
+// eviction policy less accurate, the entry itself stays in the cache.
+type Buffer[T any] struct {
+	stripes []stripe[T]
+	dropped atomic.Uint64
+}
+
+type stripe[T any] struct {
+	// head is the next slot to drain, it is only changed by the drainer.
+	head  atomic.Uint64
+	tail  atomic.Uint64
+	slots [stripeSize]atomic.Pointer[T]
+	// The padding keeps stripes on separate cache lines.
+	_ [64]byte
+}
+
+func New[T any]() *Buffer[T] {
+	stripes := 1
+	for stripes < runtime.GOMAXPROCS(0)*2 && stripes < maxStripes {
+		stripes *= 2
+	}
+
+	return &Buffer[T]{
+		stripes: make([]stripe[T], stripes),
+	}
+}
+
+// Add records the read of v. It reports whether the stripe is full and the
+// buffer should be drained.
+func (b *Buffer[T]) Add(v *T) bool {
+	s := &b.stripes[rand.Uint32()&uint32(len(b.stripes)-1)]
+
+	head := s.head.Load()
+	tail := s.tail.Load()
+	if tail-head >= stripeSize || !s.tail.CompareAndSwap(tail, tail+1) {
+		b.dropped.Add(1)
+		return true
+	}
+
+	s.slots[tail&stripeMask].Store(v)
+	return tail+1-head >= stripeSize
+}
+
+// Drain calls fn for every recorded read and empties the buffer. It must
+// not be called concurrently with itself.
+func (b *Buffer[T]) Drain(fn func(v *T)) {
+	for i := range b.stripes {
+		b.stripes[i].drain(fn)
+	}
+}
+
+// Dropped returns the number of reads which were not recorded.
+func (b *Buffer[T]) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+func (s *stripe[T]) drain(fn func(v *T)) {
+	head := s.head.Load()
+	tail := s.tail.Load()
+
+	for ; head != tail; head++ {
+		v := s.slots[head&stripeMask].Swap(nil)
+		if v == nil {
+			// The slot was claimed but is not written yet, the rest of the
+			// stripe is drained next time.
+			break
+		}
+
+		fn(v)
+	}
+
+	s.head.Store(head)
+}
@@ -0,0 +1,89 @@
+package readbuf
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuffer(t *testing.T) {
+	t.Run("Drain returns recorded reads", func(t *testing.T) {
+		b := New[int]()
+		values := []int{1, 2, 3}
+		for i := range values {
+			b.Add(&values[i])
+		}
+
+		var drained []int
+		b.Drain(func(v *int) {
+			drained = append(drained, *v)
+		})
+
+		assert.ElementsMatch(t, values, drained)
+		assert.Zero(t, b.Dropped())
+	})
+
+	t.Run("Drain empties the buffer", func(t *testing.T) {
+		b := New[int]()
+		v := 1
+		b.Add(&v)
+		b.Drain(func(*int) {})
+
+		calls := 0
+		b.Drain(func(*int) {
+			calls++
+		})
+		assert.Zero(t, calls)
+	})
+
+	t.Run("Full stripe drops reads", func(t *testing.T) {
+		b := &Buffer[int]{stripes: make([]stripe[int], 1)}
+		v := 1
+		for range stripeSize - 1 {
+			assert.False(t, b.Add(&v))
+		}
+		assert.True(t, b.Add(&v))
+
+		assert.True(t, b.Add(&v))
+		assert.Equal(t, uint64(1), b.Dropped())
+
+		calls := 0
+		b.Drain(func(*int) {
+			calls++
+		})
+		assert.Equal(t, stripeSize, calls)
+	})
+
+	t.Run("Concurrent reads are recorded or dropped", func(t *testing.T) {
+		b := New[int]()
+		v := 1
+		var drained int
+		var mu sync.Mutex
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 1000 {
+					if b.Add(&v) && mu.TryLock() {
+						b.Drain(func(*int) {
+							drained++
+						})
+						mu.Unlock()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		mu.Lock()
+		b.Drain(func(*int) {
+			drained++
+		})
+		mu.Unlock()
+
+		assert.Equal(t, 8000, drained+int(b.Dropped()))
+	})
+}
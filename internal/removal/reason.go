@@ -0,0 +1,30 @@
+package removal
+
+// Reason describes why an entry has left a cache.
+type Reason int
+
+const (
+	// Capacity means the entry was evicted to free space for another one.
+	Capacity Reason = iota + 1
+	// Expired means the entry outlived its time to live.
+	Expired
+	// Deleted means the entry was removed by Delete or Clear.
+	Deleted
+	// Replaced means the entry value was overwritten by Set.
+	Replaced
+)
+
+func (r Reason) String() string {
+	switch r {
+	case Capacity:
+		return "capacity"
+	case Expired:
+		return "expired"
+	case Deleted:
+		return "deleted"
+	case Replaced:
+		return "replaced"
+	default:
+		return "unknown"
+	}
+}
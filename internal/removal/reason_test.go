@@ -0,0 +1,20 @@
+package removal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReason_String(t *testing.T) {
+	t.Run("Known reasons", func(t *testing.T) {
+		assert.Equal(t, "capacity", Capacity.String())
+		assert.Equal(t, "expired", Expired.String())
+		assert.Equal(t, "deleted", Deleted.String())
+		assert.Equal(t, "replaced", Replaced.String())
+	})
+
+	t.Run("Unknown reason", func(t *testing.T) {
+		assert.Equal(t, "unknown", Reason(0).String())
+	})
+}
@@ -0,0 +1,373 @@
+package s3fifocache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/list"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+const smallPercent = 10
+
+// Cache is an S3-FIFO cache made of three FIFO queues. New entries enter
+// the small queue, which takes about 10% of the cache. An entry leaving the
+// small queue moves to the main queue if it was hit more than once, and is
+// evicted otherwise, leaving its key in the ghost queue. A new entry whose
+// key is in the ghost queue enters the main queue directly. An entry
+// leaving the main queue is reinserted while it has hits left. Hits only
+// increment a 2-bit counter, so concurrent hits share a read lock, and
+// one-time keys of a scan never reach the main queue.
+type Cache[K comparable, V any] struct {
+	data       map[K]*entry[K, V]
+	small      *list.List[*entry[K, V]]
+	main       *list.List[*entry[K, V]]
+	expiry     *lifecycle.ExpiryQueue[*entry[K, V]]
+	ghost      *ghostQueue[K]
+	capacity   int
+	maxBytes   int64
+	bytes      int64
+	smallBytes int64
+	sizer      func(key K, value V) int64
+	ttl        time.Duration
+	janitor    *janitor.Janitor
+	stats      *lifecycle.Recorder[K, V]
+	mu         sync.RWMutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.MaxBytes < 0 {
+		return nil, ErrIllegalMaxBytes
+	}
+
+	if params.Capacity < 0 || (params.Capacity == 0 && params.MaxBytes <= 0) {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.MaxBytes > 0 && params.Sizer == nil {
+		return nil, ErrSizerRequired
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	cache := Cache[K, V]{
+		data:     make(map[K]*entry[K, V], params.Capacity),
+		small:    list.New[*entry[K, V]](),
+		main:     list.New[*entry[K, V]](),
+		expiry:   lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		ghost:    newGhostQueue[K](),
+		capacity: params.Capacity,
+		maxBytes: params.MaxBytes,
+		sizer:    params.Sizer,
+		ttl:      params.TTL,
+		stats:    lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+// Get returns the value stored by the key. A hit only increments the
+// frequency counter of the entry, so concurrent hits share a read lock. An
+// expired entry is removed under the write lock.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	v, ok := c.data[key]
+	if ok && !lifecycle.IsExpired(v.expiredAt) {
+		v.touch()
+		value := v.value
+		c.mu.RUnlock()
+
+		c.stats.Hit()
+		return value, true
+	}
+	c.mu.RUnlock()
+
+	if ok {
+		c.removeExpired(key)
+	}
+
+	c.stats.Miss()
+	return c.getZeroValue(), false
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.data)
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		c.stats.Removal(v.key, v.value, removal.Deleted)
+	}
+
+	clear(c.data)
+	c.small.Clear()
+	c.main.Clear()
+	c.expiry.Clear()
+	c.ghost.Clear()
+	c.bytes = 0
+	c.smallBytes = 0
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stats.Snapshot(len(c.data))
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	size := c.sizeOf(key, value)
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return ErrEntryTooLarge
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	if ok {
+		c.updateEntry(v, value, size, expiredAt)
+	} else {
+		c.addNewEntry(key, value, size, expiredAt)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, size int64, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	c.resize(entry, size)
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	entry.touch()
+
+	c.evictOverflow(entry)
+}
+
+func (c *Cache[K, V]) addNewEntry(key K, value V, size int64, expiredAt time.Time) {
+	entry := newEntry(key, value, expiredAt)
+	entry.size = size
+	c.data[key] = entry
+	c.bytes += size
+	entry.expiry = c.expiry.Push(entry)
+
+	if c.ghost.Remove(key) {
+		entry.element = c.main.PushBack(entry)
+	} else {
+		entry.inSmall = true
+		entry.element = c.small.PushBack(entry)
+		c.smallBytes += size
+	}
+
+	c.evictOverflow(entry)
+}
+
+// evictOverflow evicts entries until the cache fits its limits. The except
+// entry, which has just been stored, is never evicted. If it is the only
+// entry of the main queue, the small queue is evicted instead, so that every
+// iteration makes progress.
+func (c *Cache[K, V]) evictOverflow(except *entry[K, V]) {
+	for c.isOverflowed() && len(c.data) > 1 {
+		onlyExceptInMain := c.main.Len() == 1 && c.main.Front().Value == except
+		if c.small.Len() > 0 && (c.isSmallOverTarget() || c.main.Len() == 0 || onlyExceptInMain) {
+			c.evictSmall(except)
+		} else {
+			c.evictMain(except)
+		}
+	}
+}
+
+// evictSmall moves the oldest entry of the small queue to the main queue
+// if it was hit more than once, and evicts it otherwise.
+func (c *Cache[K, V]) evictSmall(except *entry[K, V]) {
+	oldest := c.small.Front().Value
+	if oldest == except || oldest.freq.Load() > 1 {
+		c.small.Remove(oldest.element)
+		c.smallBytes -= oldest.size
+		oldest.inSmall = false
+		oldest.element = c.main.PushBack(oldest)
+		return
+	}
+
+	c.ghost.Add(oldest.key, c.ghostLimit())
+	c.removeEntry(oldest, removal.Capacity)
+}
+
+// evictMain reinserts the oldest entry of the main queue if it has hits
+// left, and evicts it otherwise.
+func (c *Cache[K, V]) evictMain(except *entry[K, V]) {
+	oldest := c.main.Front().Value
+	if oldest == except {
+		c.main.MoveToBack(oldest.element)
+		return
+	}
+
+	if freq := oldest.freq.Load(); freq > 0 {
+		oldest.freq.Store(freq - 1)
+		c.main.MoveToBack(oldest.element)
+		return
+	}
+
+	c.removeEntry(oldest, removal.Capacity)
+}
+
+func (c *Cache[K, V]) isOverflowed() bool {
+	if c.capacity > 0 && len(c.data) > c.capacity {
+		return true
+	}
+
+	return c.maxBytes > 0 && c.bytes > c.maxBytes
+}
+
+func (c *Cache[K, V]) isSmallOverTarget() bool {
+	if c.capacity > 0 && c.small.Len() >= max(c.capacity*smallPercent/100, 1) {
+		return true
+	}
+
+	return c.maxBytes > 0 && c.smallBytes >= c.maxBytes*smallPercent/100
+}
+
+// ghostLimit returns how many keys the ghost queue remembers, which is the
+// number of entries the cache can hold.
+func (c *Cache[K, V]) ghostLimit() int {
+	if c.capacity > 0 {
+		return c.capacity
+	}
+
+	return max(len(c.data), 1)
+}
+
+func (c *Cache[K, V]) resize(entry *entry[K, V], size int64) {
+	c.bytes += size - entry.size
+	if entry.inSmall {
+		c.smallBytes += size - entry.size
+	}
+	entry.size = size
+}
+
+func (c *Cache[K, V]) sizeOf(key K, value V) int64 {
+	if c.sizer == nil {
+		return 0
+	}
+
+	return c.sizer(key, value)
+}
+
+// removeExpired removes the entry by the key if it is still expired once
+// the write lock is taken.
+func (c *Cache[K, V]) removeExpired(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.bytes -= entry.size
+	if entry.inSmall {
+		c.smallBytes -= entry.size
+	}
+	c.small.Remove(entry.element)
+	c.main.Remove(entry.element)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
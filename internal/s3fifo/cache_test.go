@@ -0,0 +1,271 @@
+package s3fifocache
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	"github.com/conacry/inmem-cache/internal/list"
+	lrucache "github.com/conacry/inmem-cache/internal/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, MaxBytes: -1})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalMaxBytes)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{MaxBytes: 10})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrSizerRequired)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestCache_NewValue_ValueEnteredSmallQueue() {
+	cache := s.newCache(10)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+
+	assert.Equal(s.T(), []string{"key1"}, queueKeys(cache.small))
+	assert.Empty(s.T(), queueKeys(cache.main))
+}
+
+func (s *CacheSuite) TestCache_ValueWasNotHit_ValueWasEvictedToGhost() {
+	cache := s.newCache(10)
+
+	for i := range 11 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+
+	_, exists := cache.Get("key0")
+	assert.False(s.T(), exists)
+	assert.Equal(s.T(), 1, cache.ghost.Len())
+
+	require.NoError(s.T(), cache.Set("key0", 0))
+	assert.Contains(s.T(), queueKeys(cache.main), "key0", "a ghost key should enter the main queue")
+	assert.NotContains(s.T(), cache.ghost.keys, "key0")
+}
+
+func (s *CacheSuite) TestCache_ValueWasHitTwice_ValueWasMovedToMain() {
+	cache := s.newCache(10)
+
+	require.NoError(s.T(), cache.Set("hot", 0))
+	cache.Get("hot")
+	cache.Get("hot")
+
+	for i := range 10 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+
+	_, exists := cache.Get("hot")
+	assert.True(s.T(), exists)
+	assert.Contains(s.T(), queueKeys(cache.main), "hot")
+}
+
+func (s *CacheSuite) TestCache_MainValueWasHit_ValueWasReinserted() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	require.NoError(s.T(), cache.Set("key3", 3))
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.Equal(s.T(), []string{"key1"}, queueKeys(cache.main))
+	cache.Get("key1")
+
+	for i := range 5 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("new%d", i), i))
+	}
+
+	_, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_Scan_FrequentValuesSurvived() {
+	cache := s.newCache(100)
+
+	for range 3 {
+		for i := range 50 {
+			key := fmt.Sprintf("hot%d", i)
+			if _, ok := cache.Get(key); !ok {
+				require.NoError(s.T(), cache.Set(key, i))
+			}
+		}
+	}
+
+	for i := range 1000 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("scan%d", i), i))
+	}
+
+	for i := range 50 {
+		_, exists := cache.Get(fmt.Sprintf("hot%d", i))
+		assert.True(s.T(), exists, "hot%d should survive the scan", i)
+	}
+}
+
+func (s *CacheSuite) TestCache_NotEnoughBytes_ValuesWereEvictedUntilNewValueFits() {
+	cache, err := NewCache[string, string](InitParam[string, string]{
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	})
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), cache.Set("key1", "1234"))
+	require.NoError(s.T(), cache.Set("key2", "1234"))
+	require.NoError(s.T(), cache.Set("key3", "12345678"))
+
+	assert.Equal(s.T(), 1, cache.Len())
+	assert.Equal(s.T(), int64(8), cache.bytes)
+	value, exists := cache.Get("key3")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "12345678", value)
+
+	err = cache.Set("key4", "12345678901")
+	assert.ErrorIs(s.T(), err, ErrEntryTooLarge)
+}
+
+func (s *CacheSuite) TestCache_UpdatedValueGrew_OtherValuesWereEvicted() {
+	cache, err := NewCache[string, string](InitParam[string, string]{
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	})
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), cache.Set("key1", "1234"))
+	require.NoError(s.T(), cache.Set("key2", "1234"))
+	require.NoError(s.T(), cache.Set("key1", "123456789"))
+
+	assert.Equal(s.T(), 1, cache.Len())
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "123456789", value)
+	assert.Equal(s.T(), int64(9), cache.bytes)
+}
+
+func (s *CacheSuite) TestCache_UpdatedValueIsOnlyMainEntry_SmallQueueWasEvicted() {
+	cache, err := NewCache[string, string](InitParam[string, string]{
+		MaxBytes: 100,
+		Sizer:    stringSizer,
+	})
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), cache.Set("a", strings.Repeat("a", 10)))
+	cache.Get("a")
+	cache.Get("a")
+	for i := range 12 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), strings.Repeat("k", 10)))
+	}
+	require.NoError(s.T(), cache.Set("a", strings.Repeat("a", 95)))
+	require.NoError(s.T(), cache.Set("s", strings.Repeat("s", 4)))
+
+	done := make(chan error)
+	go func() {
+		done <- cache.Set("a", strings.Repeat("a", 97))
+	}()
+
+	select {
+	case err = <-done:
+		require.NoError(s.T(), err)
+	case <-time.After(time.Second):
+		s.T().Fatal("set did not return")
+	}
+
+	assert.Equal(s.T(), 1, cache.Len())
+	assert.Equal(s.T(), int64(97), cache.bytes)
+	_, exists := cache.Get("a")
+	assert.True(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_RandomMaxBytesWorkload_SetAlwaysReturned() {
+	for _, maxBytes := range []int64{50, 100} {
+		cache, err := NewCache[string, string](InitParam[string, string]{
+			MaxBytes: maxBytes,
+			Sizer:    stringSizer,
+		})
+		require.NoError(s.T(), err)
+
+		rnd := rand.New(rand.NewPCG(1, 1))
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range 10_000 {
+				key := fmt.Sprintf("key%d", rnd.IntN(20))
+				if rnd.IntN(2) == 0 {
+					cache.Get(key)
+					continue
+				}
+				_ = cache.Set(key, strings.Repeat("v", 1+rnd.IntN(int(maxBytes))))
+			}
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			s.T().Fatalf("workload did not finish, max bytes: %d", maxBytes)
+		}
+
+		assert.LessOrEqual(s.T(), cache.bytes, maxBytes)
+	}
+}
+
+func (s *CacheSuite) newCache(capacity int) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+func queueKeys(l *list.List[*entry[string, int]]) []string {
+	var keys []string
+	for e := l.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.key)
+	}
+
+	return keys
+}
+
+func stringSizer(_ string, value string) int64 {
+	return int64(len(value))
+}
+
+// BenchmarkCache_HitRatio replays the same traces against S3-FIFO and LRU
+// and reports the hit ratio of each.
+func BenchmarkCache_HitRatio(b *testing.B) {
+	const capacity = 1_000
+
+	policies := []cachetest.Policy{
+		{Name: "s3fifo", NewCache: func() (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+		}},
+		{Name: "lru", NewCache: func() (cachetest.Cache, error) {
+			return lrucache.NewCache[string, int](lrucache.InitParam[string, int]{Capacity: capacity, TTL: time.Hour})
+		}},
+	}
+
+	cachetest.BenchmarkHitRatio(b, cachetest.DefaultTraces()[:2], policies)
+}
@@ -0,0 +1,47 @@
+package s3fifocache
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/list"
+)
+
+// maxFreq is the largest value of the 2-bit frequency counter.
+const maxFreq = 3
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+	size      int64
+	// freq counts hits up to maxFreq. Hits update it under the read lock,
+	// so it is atomic.
+	freq    atomic.Uint32
+	inSmall bool
+	element *list.Element[*entry[K, V]]
+	expiry  *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiredAt: expiredAt,
+	}
+}
+
+// touch increments the frequency counter unless it is saturated.
+func (e *entry[K, V]) touch() {
+	for {
+		freq := e.freq.Load()
+		if freq >= maxFreq || e.freq.CompareAndSwap(freq, freq+1) {
+			return
+		}
+	}
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
@@ -0,0 +1,31 @@
+package s3fifocache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEntry(t *testing.T) {
+	t.Run("Create new entry", func(t *testing.T) {
+		expiredAt := time.Now().Add(10 * time.Second)
+		entry := newEntry("key", "value", expiredAt)
+
+		assert.Equal(t, "key", entry.key)
+		assert.Equal(t, "value", entry.value)
+		assert.Equal(t, expiredAt, entry.expiredAt)
+		assert.Zero(t, entry.freq.Load())
+	})
+}
+
+func TestEntry_Touch(t *testing.T) {
+	t.Run("Frequency saturates at 3", func(t *testing.T) {
+		entry := newEntry("key", "value", time.Time{})
+		for range 10 {
+			entry.touch()
+		}
+
+		assert.Equal(t, uint32(maxFreq), entry.freq.Load())
+	})
+}
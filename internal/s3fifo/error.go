@@ -0,0 +1,18 @@
+package s3fifocache
+
+import (
+	"errors"
+
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+)
+
+var (
+	ErrIllegalCapacity        = errors.New("capacity should be greater than 0 unless max bytes is set")
+	ErrIllegalTTL             = errors.New("ttl should not be negative")
+	ErrIllegalCleanupInterval = errors.New("cleanup interval should not be negative")
+	ErrIllegalEntryTTL        = lifecycle.ErrIllegalEntryTTL
+	ErrIllegalDeadline        = lifecycle.ErrIllegalDeadline
+	ErrIllegalMaxBytes        = errors.New("max bytes should not be negative")
+	ErrSizerRequired          = errors.New("sizer is required when max bytes is set")
+	ErrEntryTooLarge          = errors.New("entry size exceeds max bytes")
+)
@@ -0,0 +1,55 @@
+package s3fifocache
+
+import (
+	"github.com/conacry/inmem-cache/internal/list"
+)
+
+// ghostQueue remembers keys of entries evicted from the small queue in
+// FIFO order. It holds keys only.
+type ghostQueue[K comparable] struct {
+	keys  map[K]*list.Element[K]
+	queue *list.List[K]
+}
+
+func newGhostQueue[K comparable]() *ghostQueue[K] {
+	return &ghostQueue[K]{
+		keys:  make(map[K]*list.Element[K]),
+		queue: list.New[K](),
+	}
+}
+
+func (q *ghostQueue[K]) Len() int {
+	return q.queue.Len()
+}
+
+// Add remembers the key and forgets the oldest keys beyond limit.
+func (q *ghostQueue[K]) Add(key K, limit int) {
+	if _, ok := q.keys[key]; ok {
+		return
+	}
+
+	q.keys[key] = q.queue.PushBack(key)
+
+	for q.queue.Len() > limit {
+		oldest := q.queue.Front()
+		q.queue.Remove(oldest)
+		delete(q.keys, oldest.Value)
+	}
+}
+
+// Remove forgets the key and reports whether it was remembered.
+func (q *ghostQueue[K]) Remove(key K) bool {
+	element, ok := q.keys[key]
+	if !ok {
+		return false
+	}
+
+	q.queue.Remove(element)
+	delete(q.keys, key)
+	return true
+}
+
+func (q *ghostQueue[K]) Clear() {
+	clear(q.keys)
+	q.queue.Clear()
+}
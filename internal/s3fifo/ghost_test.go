@@ -0,0 +1,48 @@
+package s3fifocache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGhostQueue(t *testing.T) {
+	t.Run("Added key is remembered", func(t *testing.T) {
+		q := newGhostQueue[string]()
+		q.Add("key1", 10)
+
+		assert.Equal(t, 1, q.Len())
+		assert.True(t, q.Remove("key1"))
+		assert.False(t, q.Remove("key1"))
+		assert.Zero(t, q.Len())
+	})
+
+	t.Run("Oldest keys beyond limit are forgotten", func(t *testing.T) {
+		q := newGhostQueue[string]()
+		q.Add("key1", 2)
+		q.Add("key2", 2)
+		q.Add("key3", 2)
+
+		assert.Equal(t, 2, q.Len())
+		assert.False(t, q.Remove("key1"))
+		assert.True(t, q.Remove("key2"))
+		assert.True(t, q.Remove("key3"))
+	})
+
+	t.Run("Key is added once", func(t *testing.T) {
+		q := newGhostQueue[string]()
+		q.Add("key1", 10)
+		q.Add("key1", 10)
+
+		assert.Equal(t, 1, q.Len())
+	})
+
+	t.Run("Clear forgets all keys", func(t *testing.T) {
+		q := newGhostQueue[string]()
+		q.Add("key1", 10)
+
+		q.Clear()
+		assert.Zero(t, q.Len())
+		assert.False(t, q.Remove("key1"))
+	})
+}
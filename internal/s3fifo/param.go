@@ -0,0 +1,24 @@
+package s3fifocache
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/removal"
+)
+
+type InitParam[K comparable, V any] struct {
+	// Capacity limits the number of entries, 0 means the number of entries
+	// is limited by MaxBytes only.
+	Capacity int
+	// MaxBytes limits the total size of entries measured by Sizer, 0 means
+	// there is no limit.
+	MaxBytes int64
+	Sizer    func(key K, value V) int64
+	// TTL is the default time to live of entries, 0 means entries never
+	// expire.
+	TTL             time.Duration
+	CleanupInterval time.Duration
+	// OnEvict is called for every entry leaving the cache. It runs while
+	// the cache lock is held, so it must not call the cache.
+	OnEvict func(key K, value V, reason removal.Reason)
+}
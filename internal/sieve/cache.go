@@ -0,0 +1,262 @@
+package sievecache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/list"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+// Cache is a SIEVE cache. Entries are kept in insertion order and a hit
+// only marks the entry as visited, so hits share a read lock. To evict, the
+// hand moves from the oldest entry to newer ones, clearing the visited
+// marks, and evicts the first entry which was not visited. The hand keeps
+// its position between evictions.
+type Cache[K comparable, V any] struct {
+	data     map[K]*entry[K, V]
+	queue    *list.List[*entry[K, V]]
+	hand     *list.Element[*entry[K, V]]
+	expiry   *lifecycle.ExpiryQueue[*entry[K, V]]
+	capacity int
+	ttl      time.Duration
+	janitor  *janitor.Janitor
+	stats    *lifecycle.Recorder[K, V]
+	mu       sync.RWMutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.Capacity <= 0 {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	cache := Cache[K, V]{
+		data:     make(map[K]*entry[K, V], params.Capacity),
+		queue:    list.New[*entry[K, V]](),
+		expiry:   lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		capacity: params.Capacity,
+		ttl:      params.TTL,
+		stats:    lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+// Get returns the value stored by the key. A hit only marks the entry as
+// visited, so concurrent hits share a read lock. An expired entry is
+// removed under the write lock.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	v, ok := c.data[key]
+	if ok && !lifecycle.IsExpired(v.expiredAt) {
+		v.visited.Store(true)
+		value := v.value
+		c.mu.RUnlock()
+
+		c.stats.Hit()
+		return value, true
+	}
+	c.mu.RUnlock()
+
+	if ok {
+		c.removeExpired(key)
+	}
+
+	c.stats.Miss()
+	return c.getZeroValue(), false
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.data)
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		c.stats.Removal(v.key, v.value, removal.Deleted)
+	}
+
+	clear(c.data)
+	c.queue.Clear()
+	c.expiry.Clear()
+	c.hand = nil
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stats.Snapshot(len(c.data))
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	if ok {
+		c.updateEntry(v, value, expiredAt)
+	} else {
+		c.addNewEntry(key, value, expiredAt)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	entry.visited.Store(true)
+}
+
+func (c *Cache[K, V]) addNewEntry(key K, value V, expiredAt time.Time) {
+	if len(c.data) >= c.capacity {
+		c.evict()
+	}
+
+	entry := newEntry(key, value, expiredAt)
+	entry.element = c.queue.PushBack(entry)
+	entry.expiry = c.expiry.Push(entry)
+	c.data[key] = entry
+}
+
+// evict moves the hand to the first entry which was not visited since the
+// hand passed it last time and evicts it.
+func (c *Cache[K, V]) evict() {
+	hand := c.hand
+	if hand == nil {
+		hand = c.queue.Front()
+	}
+
+	for hand.Value.visited.Load() {
+		hand.Value.visited.Store(false)
+
+		hand = hand.Next()
+		if hand == nil {
+			hand = c.queue.Front()
+		}
+	}
+
+	c.hand = hand
+	c.removeEntry(hand.Value, removal.Capacity)
+}
+
+// removeExpired removes the entry by the key if it is still expired once
+// the write lock is taken.
+func (c *Cache[K, V]) removeExpired(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	if c.hand == entry.element {
+		c.hand = entry.element.Next()
+	}
+
+	delete(c.data, entry.key)
+	c.queue.Remove(entry.element)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
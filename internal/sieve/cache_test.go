@@ -0,0 +1,144 @@
+package sievecache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	lrucache "github.com/conacry/inmem-cache/internal/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestCache_Hit_OrderWasNotChanged() {
+	cache := s.newCache(3)
+
+	for i := range 3 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+	cache.Get("key0")
+
+	assert.Equal(s.T(), []string{"key0", "key1", "key2"}, queueKeys(cache))
+	assert.True(s.T(), cache.data["key0"].visited.Load())
+}
+
+func (s *CacheSuite) TestCache_NotEnoughCapacity_UnvisitedValueWasEvicted() {
+	cache := s.newCache(3)
+
+	for i := range 3 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+	cache.Get("key0")
+
+	require.NoError(s.T(), cache.Set("key3", 3))
+
+	assert.Equal(s.T(), []string{"key0", "key2", "key3"}, queueKeys(cache))
+	assert.False(s.T(), cache.data["key0"].visited.Load(), "the hand should clear the visited mark")
+	assert.Equal(s.T(), "key2", cache.hand.Value.key)
+}
+
+func (s *CacheSuite) TestCache_HandKeptPosition_NextEvictionStartedFromHand() {
+	cache := s.newCache(3)
+
+	for i := range 3 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+	cache.Get("key0")
+	require.NoError(s.T(), cache.Set("key3", 3))
+	cache.Get("key0")
+
+	require.NoError(s.T(), cache.Set("key4", 4))
+
+	assert.Equal(s.T(), []string{"key0", "key3", "key4"}, queueKeys(cache))
+	assert.Equal(s.T(), "key3", cache.hand.Value.key)
+}
+
+func (s *CacheSuite) TestCache_AllValuesVisited_HandWrappedAround() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("key0", 0))
+	require.NoError(s.T(), cache.Set("key1", 1))
+	cache.Get("key0")
+	cache.Get("key1")
+
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	assert.Equal(s.T(), []string{"key1", "key2"}, queueKeys(cache))
+}
+
+func (s *CacheSuite) TestCache_DeleteValueUnderHand_HandWasMoved() {
+	cache := s.newCache(3)
+
+	for i := range 3 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+	cache.Get("key0")
+	require.NoError(s.T(), cache.Set("key3", 3))
+	require.Equal(s.T(), "key2", cache.hand.Value.key)
+
+	assert.True(s.T(), cache.Delete("key2"))
+	assert.Equal(s.T(), "key3", cache.hand.Value.key)
+
+	require.NoError(s.T(), cache.Set("key4", 4))
+	require.NoError(s.T(), cache.Set("key5", 5))
+	assert.Equal(s.T(), 3, cache.Len())
+}
+
+func (s *CacheSuite) newCache(capacity int) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+func queueKeys(cache *Cache[string, int]) []string {
+	var keys []string
+	for e := cache.queue.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.key)
+	}
+
+	return keys
+}
+
+// BenchmarkCache_HitRatio replays the same traces against SIEVE and LRU and
+// reports the hit ratio of each.
+func BenchmarkCache_HitRatio(b *testing.B) {
+	const capacity = 1_000
+
+	policies := []cachetest.Policy{
+		{Name: "sieve", NewCache: func() (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+		}},
+		{Name: "lru", NewCache: func() (cachetest.Cache, error) {
+			return lrucache.NewCache[string, int](lrucache.InitParam[string, int]{Capacity: capacity, TTL: time.Hour})
+		}},
+	}
+
+	cachetest.BenchmarkHitRatio(b, cachetest.DefaultTraces(), policies)
+}
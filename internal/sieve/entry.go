@@ -0,0 +1,31 @@
+package sievecache
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/list"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+	// visited is set by hits under the read lock, so it is atomic.
+	visited atomic.Bool
+	element *list.Element[*entry[K, V]]
+	expiry  *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiredAt: expiredAt,
+	}
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
@@ -0,0 +1,268 @@
+package slrucache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/list"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+// Cache is a segmented LRU cache. New entries are added to the
+// probationary segment, and a hit there promotes the entry to the protected
+// segment. When the protected segment is full, its least recently used
+// entry is demoted back to the probationary one. Entries are evicted from
+// the probationary segment first, so entries seen once never evict entries
+// which were used again.
+type Cache[K comparable, V any] struct {
+	data         map[K]*entry[K, V]
+	probationary *list.List[*entry[K, V]]
+	protected    *list.List[*entry[K, V]]
+	expiry       *lifecycle.ExpiryQueue[*entry[K, V]]
+	capacity     int
+	// protectedCapacity limits the number of entries in the protected
+	// segment.
+	protectedCapacity int
+	ttl               time.Duration
+	janitor           *janitor.Janitor
+	stats             *lifecycle.Recorder[K, V]
+	mu                sync.Mutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.Capacity <= 0 {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.ProtectedRatio < 0 || params.ProtectedRatio >= 1 {
+		return nil, ErrIllegalProtectedRatio
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	protectedRatio := params.ProtectedRatio
+	if protectedRatio == 0 {
+		protectedRatio = DefaultProtectedRatio
+	}
+
+	cache := Cache[K, V]{
+		data:              make(map[K]*entry[K, V], params.Capacity),
+		probationary:      list.New[*entry[K, V]](),
+		protected:         list.New[*entry[K, V]](),
+		expiry:            lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		capacity:          params.Capacity,
+		protectedCapacity: max(int(float64(params.Capacity)*protectedRatio), 1),
+		ttl:               params.TTL,
+		stats:             lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	c.moveToProtected(v)
+	c.stats.Hit()
+	return v.value, true
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.data)
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		c.stats.Removal(v.key, v.value, removal.Deleted)
+	}
+
+	clear(c.data)
+	c.probationary.Clear()
+	c.protected.Clear()
+	c.expiry.Clear()
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats.Snapshot(len(c.data))
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	if ok {
+		c.updateEntry(v, value, expiredAt)
+	} else {
+		c.addNewEntry(key, value, expiredAt)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	c.moveToProtected(entry)
+}
+
+func (c *Cache[K, V]) addNewEntry(key K, value V, expiredAt time.Time) {
+	if len(c.data) >= c.capacity {
+		c.evict()
+	}
+
+	entry := newEntry(key, value, expiredAt)
+	entry.element = c.probationary.PushBack(entry)
+	entry.expiry = c.expiry.Push(entry)
+	c.data[key] = entry
+}
+
+// evict removes the least recently used entry of the probationary segment,
+// or of the protected one if the probationary segment is empty.
+func (c *Cache[K, V]) evict() {
+	if c.probationary.Len() > 0 {
+		c.removeEntry(c.probationary.Front().Value, removal.Capacity)
+		return
+	}
+
+	c.removeEntry(c.protected.Front().Value, removal.Capacity)
+}
+
+// moveToProtected makes the entry the most recently used one of the
+// protected segment. If the segment gets over its capacity, its least
+// recently used entry is demoted to the probationary segment.
+func (c *Cache[K, V]) moveToProtected(entry *entry[K, V]) {
+	if c.protected.Contains(entry.element) {
+		c.protected.MoveToBack(entry.element)
+		return
+	}
+
+	c.probationary.Remove(entry.element)
+	entry.element = c.protected.PushBack(entry)
+
+	if c.protected.Len() > c.protectedCapacity {
+		demoted := c.protected.Front().Value
+		c.protected.Remove(demoted.element)
+		demoted.element = c.probationary.PushBack(demoted)
+	}
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.probationary.Remove(entry.element)
+	c.protected.Remove(entry.element)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
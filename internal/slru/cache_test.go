@@ -0,0 +1,168 @@
+package slrucache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	"github.com/conacry/inmem-cache/internal/list"
+	lrucache "github.com/conacry/inmem-cache/internal/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	for _, ratio := range []float64{-0.5, 1, 1.5} {
+		cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, ProtectedRatio: ratio})
+		assert.Nil(s.T(), cache)
+		assert.ErrorIs(s.T(), err, ErrIllegalProtectedRatio)
+	}
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestNewCache_ProtectedRatio_ProtectedCapacityWasSet() {
+	cache := s.newCache(10, 0)
+	assert.Equal(s.T(), 8, cache.protectedCapacity)
+
+	cache = s.newCache(10, 0.5)
+	assert.Equal(s.T(), 5, cache.protectedCapacity)
+
+	cache = s.newCache(1, 0.5)
+	assert.Equal(s.T(), 1, cache.protectedCapacity)
+}
+
+func (s *CacheSuite) TestCache_NewValue_ValueWasAddedToProbationary() {
+	cache := s.newCache(4, 0)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	assert.Equal(s.T(), []string{"key1", "key2"}, listKeys(cache.probationary))
+	assert.Empty(s.T(), listKeys(cache.protected))
+}
+
+func (s *CacheSuite) TestCache_HitInProbationary_ValueWasPromoted() {
+	cache := s.newCache(4, 0)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	cache.Get("key1")
+
+	assert.Equal(s.T(), []string{"key2"}, listKeys(cache.probationary))
+	assert.Equal(s.T(), []string{"key1"}, listKeys(cache.protected))
+}
+
+func (s *CacheSuite) TestCache_ProtectedIsFull_OldestValueWasDemoted() {
+	cache := s.newCache(4, 0.5)
+
+	for i := range 3 {
+		key := fmt.Sprintf("key%d", i)
+		require.NoError(s.T(), cache.Set(key, i))
+		cache.Get(key)
+	}
+
+	assert.Equal(s.T(), []string{"key1", "key2"}, listKeys(cache.protected))
+	assert.Equal(s.T(), []string{"key0"}, listKeys(cache.probationary))
+}
+
+func (s *CacheSuite) TestCache_NotEnoughCapacity_ProbationaryValueWasEvicted() {
+	cache := s.newCache(3, 0)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	cache.Get("key1")
+	require.NoError(s.T(), cache.Set("key2", 2))
+	require.NoError(s.T(), cache.Set("key3", 3))
+
+	require.NoError(s.T(), cache.Set("key4", 4))
+
+	assert.Equal(s.T(), []string{"key3", "key4"}, listKeys(cache.probationary))
+	assert.Equal(s.T(), []string{"key1"}, listKeys(cache.protected))
+}
+
+func (s *CacheSuite) TestCache_ProbationaryIsEmpty_ProtectedValueWasEvicted() {
+	cache := s.newCache(1, 0)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	cache.Get("key1")
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	assert.Equal(s.T(), []string{"key2"}, listKeys(cache.probationary))
+	assert.Empty(s.T(), listKeys(cache.protected))
+}
+
+func (s *CacheSuite) TestCache_Scan_ProtectedValuesSurvived() {
+	cache := s.newCache(10, 0)
+
+	for i := range 5 {
+		key := fmt.Sprintf("hot%d", i)
+		require.NoError(s.T(), cache.Set(key, i))
+		cache.Get(key)
+	}
+
+	for i := range 100 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("scan%d", i), i))
+	}
+
+	for i := range 5 {
+		value, exists := cache.Get(fmt.Sprintf("hot%d", i))
+		assert.True(s.T(), exists)
+		assert.Equal(s.T(), i, value)
+	}
+	assert.Equal(s.T(), 10, cache.Len())
+}
+
+func (s *CacheSuite) newCache(capacity int, protectedRatio float64) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: capacity, ProtectedRatio: protectedRatio})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+func listKeys(l *list.List[*entry[string, int]]) []string {
+	var keys []string
+	for e := l.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.key)
+	}
+
+	return keys
+}
+
+// BenchmarkCache_HitRatio replays the same traces against SLRU and LRU and
+// reports the hit ratio of each.
+func BenchmarkCache_HitRatio(b *testing.B) {
+	const capacity = 1_000
+
+	policies := []cachetest.Policy{
+		{Name: "slru", NewCache: func() (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+		}},
+		{Name: "lru", NewCache: func() (cachetest.Cache, error) {
+			return lrucache.NewCache[string, int](lrucache.InitParam[string, int]{Capacity: capacity, TTL: time.Hour})
+		}},
+	}
+
+	cachetest.BenchmarkHitRatio(b, cachetest.DefaultTraces(), policies)
+}
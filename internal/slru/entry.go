@@ -0,0 +1,30 @@
+package slrucache
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/list"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+	// element links the entry into the probationary or the protected
+	// segment.
+	element *list.Element[*entry[K, V]]
+	expiry  *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiredAt: expiredAt,
+	}
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
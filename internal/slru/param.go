@@ -0,0 +1,26 @@
+package slrucache
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/removal"
+)
+
+// DefaultProtectedRatio is the part of the capacity taken by the protected
+// segment when InitParam.ProtectedRatio is 0.
+const DefaultProtectedRatio = 0.8
+
+type InitParam[K comparable, V any] struct {
+	Capacity int
+	// ProtectedRatio is the part of the capacity taken by the protected
+	// segment, it should be greater than 0 and less than 1. 0 is taken as
+	// unset and means DefaultProtectedRatio.
+	ProtectedRatio float64
+	// TTL is the default time to live of entries, 0 means entries never
+	// expire.
+	TTL             time.Duration
+	CleanupInterval time.Duration
+	// OnEvict is called for every entry leaving the cache. It runs while
+	// the cache lock is held, so it must not call the cache.
+	OnEvict func(key K, value V, reason removal.Reason)
+}
@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"sync/atomic"
+
+	"github.com/conacry/inmem-cache/internal/removal"
+)
+
+// Stats is a snapshot of cache counters.
+type Stats struct {
+	Hits   uint64
+	Misses uint64
+	Sets   uint64
+	// Evictions is the number of entries evicted because of capacity.
+	Evictions    uint64
+	Expirations  uint64
+	Deletions    uint64
+	Replacements uint64
+	// Size is the number of entries in the cache at the snapshot moment.
+	Size int
+}
+
+// HitRatio returns the share of hits among all lookups, or 0 if there were
+// no lookups.
+func (s Stats) HitRatio() float64 {
+	lookups := s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(lookups)
+}
+
+// Add returns the sum of two snapshots. It is used to aggregate statistics
+// of several caches.
+func (s Stats) Add(other Stats) Stats {
+	return Stats{
+		Hits:         s.Hits + other.Hits,
+		Misses:       s.Misses + other.Misses,
+		Sets:         s.Sets + other.Sets,
+		Evictions:    s.Evictions + other.Evictions,
+		Expirations:  s.Expirations + other.Expirations,
+		Deletions:    s.Deletions + other.Deletions,
+		Replacements: s.Replacements + other.Replacements,
+		Size:         s.Size + other.Size,
+	}
+}
+
+// Counter collects cache statistics. It is safe for concurrent use.
+type Counter struct {
+	hits         atomic.Uint64
+	misses       atomic.Uint64
+	sets         atomic.Uint64
+	evictions    atomic.Uint64
+	expirations  atomic.Uint64
+	deletions    atomic.Uint64
+	replacements atomic.Uint64
+}
+
+func (c *Counter) Hit() {
+	c.hits.Add(1)
+}
+
+func (c *Counter) Miss() {
+	c.misses.Add(1)
+}
+
+func (c *Counter) Set() {
+	c.sets.Add(1)
+}
+
+func (c *Counter) Removal(reason removal.Reason) {
+	switch reason {
+	case removal.Capacity:
+		c.evictions.Add(1)
+	case removal.Expired:
+		c.expirations.Add(1)
+	case removal.Deleted:
+		c.deletions.Add(1)
+	case removal.Replaced:
+		c.replacements.Add(1)
+	}
+}
+
+func (c *Counter) Snapshot(size int) Stats {
+	return Stats{
+		Hits:         c.hits.Load(),
+		Misses:       c.misses.Load(),
+		Sets:         c.sets.Load(),
+		Evictions:    c.evictions.Load(),
+		Expirations:  c.expirations.Load(),
+		Deletions:    c.deletions.Load(),
+		Replacements: c.replacements.Load(),
+		Size:         size,
+	}
+}
+
+func (c *Counter) Reset() {
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.sets.Store(0)
+	c.evictions.Store(0)
+	c.expirations.Store(0)
+	c.deletions.Store(0)
+	c.replacements.Store(0)
+}
@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats_HitRatio(t *testing.T) {
+	t.Run("No lookups", func(t *testing.T) {
+		assert.Equal(t, 0.0, Stats{}.HitRatio())
+	})
+
+	t.Run("Hits and misses", func(t *testing.T) {
+		s := Stats{Hits: 3, Misses: 1}
+		assert.Equal(t, 0.75, s.HitRatio())
+	})
+}
+
+func TestStats_Add(t *testing.T) {
+	t.Run("Counters are summed", func(t *testing.T) {
+		a := Stats{Hits: 1, Misses: 2, Sets: 3, Evictions: 4, Expirations: 5, Deletions: 6, Replacements: 7, Size: 8}
+		b := Stats{Hits: 10, Misses: 20, Sets: 30, Evictions: 40, Expirations: 50, Deletions: 60, Replacements: 70, Size: 80}
+
+		expected := Stats{Hits: 11, Misses: 22, Sets: 33, Evictions: 44, Expirations: 55, Deletions: 66, Replacements: 77, Size: 88}
+		assert.Equal(t, expected, a.Add(b))
+	})
+}
+
+func TestCounter(t *testing.T) {
+	t.Run("Snapshot contains recorded events", func(t *testing.T) {
+		var c Counter
+		c.Hit()
+		c.Hit()
+		c.Miss()
+		c.Set()
+		c.Removal(removal.Capacity)
+		c.Removal(removal.Expired)
+		c.Removal(removal.Expired)
+		c.Removal(removal.Deleted)
+		c.Removal(removal.Replaced)
+
+		expected := Stats{
+			Hits:         2,
+			Misses:       1,
+			Sets:         1,
+			Evictions:    1,
+			Expirations:  2,
+			Deletions:    1,
+			Replacements: 1,
+			Size:         10,
+		}
+		assert.Equal(t, expected, c.Snapshot(10))
+	})
+
+	t.Run("Reset clears counters", func(t *testing.T) {
+		var c Counter
+		c.Hit()
+		c.Miss()
+		c.Set()
+		c.Removal(removal.Capacity)
+		c.Reset()
+
+		assert.Equal(t, Stats{Size: 1}, c.Snapshot(1))
+	})
+}
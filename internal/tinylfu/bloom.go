@@ -0,0 +1,54 @@
+package tinylfucache
+
+import (
+	"math/bits"
+)
+
+// bloomFilter is the doorkeeper of the admission filter. It remembers
+// hashes seen once, so that one-hit wonders do not take counters of the
+// sketch.
+type bloomFilter struct {
+	bits  []uint64
+	shift uint
+}
+
+func newBloomFilter(size int) *bloomFilter {
+	size = max(nextPowerOfTwo(size), 64)
+
+	return &bloomFilter{
+		bits:  make([]uint64, size/64),
+		shift: uint(64 - bits.TrailingZeros64(uint64(size))),
+	}
+}
+
+// Add adds the hash and reports whether it was probably added before.
+func (f *bloomFilter) Add(hash uint64) bool {
+	first, second := f.positions(hash)
+	contained := f.isSet(first) && f.isSet(second)
+
+	f.bits[first/64] |= 1 << (first % 64)
+	f.bits[second/64] |= 1 << (second % 64)
+
+	return contained
+}
+
+// Contains reports whether the hash was probably added.
+func (f *bloomFilter) Contains(hash uint64) bool {
+	first, second := f.positions(hash)
+	return f.isSet(first) && f.isSet(second)
+}
+
+func (f *bloomFilter) Clear() {
+	clear(f.bits)
+}
+
+func (f *bloomFilter) isSet(position uint64) bool {
+	return f.bits[position/64]&(1<<(position%64)) != 0
+}
+
+func (f *bloomFilter) positions(hash uint64) (uint64, uint64) {
+	first := (hash * 0x9e3779b97f4a7c15) >> f.shift
+	second := ((hash>>32 | hash<<32) * 0xbf58476d1ce4e5b9) >> f.shift
+
+	return first, second
+}
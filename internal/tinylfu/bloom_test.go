@@ -0,0 +1,26 @@
+package tinylfucache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomFilter(t *testing.T) {
+	t.Run("Added hash is contained", func(t *testing.T) {
+		f := newBloomFilter(1024)
+
+		assert.False(t, f.Contains(42))
+		assert.False(t, f.Add(42))
+		assert.True(t, f.Contains(42))
+		assert.True(t, f.Add(42))
+	})
+
+	t.Run("Clear removes hashes", func(t *testing.T) {
+		f := newBloomFilter(1024)
+		f.Add(42)
+
+		f.Clear()
+		assert.False(t, f.Contains(42))
+	})
+}
@@ -0,0 +1,322 @@
+package tinylfucache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/list"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+const (
+	windowPercent    = 1
+	protectedPercent = 80
+)
+
+// Cache is a W-TinyLFU cache. New entries enter a small window LRU. An
+// entry leaving the window competes with the oldest entry of the main
+// segmented LRU, and the admission filter keeps the one which was accessed
+// more often recently. The main LRU is split into a probation segment for
+// entries accessed once since admission and a protected segment for
+// entries accessed again.
+type Cache[K comparable, V any] struct {
+	data         map[K]*entry[K, V]
+	window       *list.List[*entry[K, V]]
+	probation    *list.List[*entry[K, V]]
+	protected    *list.List[*entry[K, V]]
+	expiry       *lifecycle.ExpiryQueue[*entry[K, V]]
+	filter       *admissionFilter
+	hasher       func(key K) uint64
+	capacity     int
+	windowCap    int
+	protectedCap int
+	ttl          time.Duration
+	janitor      *janitor.Janitor
+	stats        *lifecycle.Recorder[K, V]
+	mu           sync.Mutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.Capacity <= 0 {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.Hasher == nil {
+		return nil, ErrHasherRequired
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	windowCap := max(params.Capacity*windowPercent/100, 1)
+
+	cache := Cache[K, V]{
+		data:         make(map[K]*entry[K, V], params.Capacity),
+		window:       list.New[*entry[K, V]](),
+		probation:    list.New[*entry[K, V]](),
+		protected:    list.New[*entry[K, V]](),
+		expiry:       lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		filter:       newAdmissionFilter(params.Capacity),
+		hasher:       params.Hasher,
+		capacity:     params.Capacity,
+		windowCap:    windowCap,
+		protectedCap: (params.Capacity - windowCap) * protectedPercent / 100,
+		ttl:          params.TTL,
+		stats:        lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		c.filter.Record(c.hasher(key))
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	c.filter.Record(v.hash)
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	c.touch(v)
+	c.stats.Hit()
+	return v.value, true
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.data)
+}
+
+// Clear removes all entries. The access history kept by the admission
+// filter is cleared as well.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		c.stats.Removal(v.key, v.value, removal.Deleted)
+	}
+
+	clear(c.data)
+	c.window.Clear()
+	c.probation.Clear()
+	c.protected.Clear()
+	c.expiry.Clear()
+	c.filter.Clear()
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats.Snapshot(len(c.data))
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	if ok {
+		c.updateEntry(v, value, expiredAt)
+	} else {
+		c.addNewEntry(key, value, expiredAt)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	c.filter.Record(entry.hash)
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	c.touch(entry)
+}
+
+func (c *Cache[K, V]) addNewEntry(key K, value V, expiredAt time.Time) {
+	var candidate *entry[K, V]
+
+	entry := newEntry(key, value, c.hasher(key), expiredAt)
+	c.filter.Record(entry.hash)
+
+	c.data[key] = entry
+	entry.expiry = c.expiry.Push(entry)
+	c.pushBack(c.window, windowSegment, entry)
+
+	for c.window.Len() > c.windowCap {
+		candidate = c.window.Front().Value
+		c.window.Remove(candidate.element)
+		c.pushBack(c.probation, probationSegment, candidate)
+	}
+
+	c.evictOverflow(candidate)
+}
+
+// evictOverflow evicts entries until the cache fits its capacity. The
+// candidate which has just left the window competes with the oldest entry
+// of the main LRU, the less frequently used of them is evicted.
+func (c *Cache[K, V]) evictOverflow(candidate *entry[K, V]) {
+	for len(c.data) > c.capacity {
+		victim := c.mainVictim()
+		if victim == nil {
+			victim = c.window.Front().Value
+		}
+
+		if candidate != nil && candidate != victim && c.data[candidate.key] == candidate &&
+			c.filter.Estimate(candidate.hash) <= c.filter.Estimate(victim.hash) {
+			victim = candidate
+		}
+
+		c.removeEntry(victim, removal.Capacity)
+	}
+}
+
+// mainVictim returns the oldest entry of the main LRU, preferring the
+// probation segment.
+func (c *Cache[K, V]) mainVictim() *entry[K, V] {
+	if e := c.probation.Front(); e != nil {
+		return e.Value
+	}
+
+	if e := c.protected.Front(); e != nil {
+		return e.Value
+	}
+
+	return nil
+}
+
+// touch moves the accessed entry to the back of its segment. An entry of
+// the probation segment is promoted to the protected one, which demotes
+// the oldest protected entry when the protected segment is full.
+func (c *Cache[K, V]) touch(entry *entry[K, V]) {
+	switch entry.segment {
+	case windowSegment:
+		c.window.MoveToBack(entry.element)
+	case protectedSegment:
+		c.protected.MoveToBack(entry.element)
+	case probationSegment:
+		c.probation.Remove(entry.element)
+		c.pushBack(c.protected, protectedSegment, entry)
+
+		for c.protected.Len() > c.protectedCap {
+			demoted := c.protected.Front().Value
+			c.protected.Remove(demoted.element)
+			c.pushBack(c.probation, probationSegment, demoted)
+		}
+	}
+}
+
+func (c *Cache[K, V]) pushBack(l *list.List[*entry[K, V]], segment segment, entry *entry[K, V]) {
+	entry.segment = segment
+	entry.element = l.PushBack(entry)
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.window.Remove(entry.element)
+	c.probation.Remove(entry.element)
+	c.protected.Remove(entry.element)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
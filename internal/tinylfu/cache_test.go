@@ -0,0 +1,175 @@
+package tinylfucache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/list"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{Hasher: stringHasher()})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrHasherRequired)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, Hasher: stringHasher(), TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, Hasher: stringHasher(), CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestNewCache_SegmentsWereSized() {
+	cache := s.newCache(1000)
+
+	assert.Equal(s.T(), 10, cache.windowCap)
+	assert.Equal(s.T(), 792, cache.protectedCap)
+}
+
+func (s *CacheSuite) TestCache_NewValue_ValueEnteredWindow() {
+	cache := s.newCache(100)
+
+	err := cache.Set("key1", 1)
+	require.NoError(s.T(), err)
+
+	assert.Equal(s.T(), []string{"key1"}, listKeys(cache.window))
+	assert.Empty(s.T(), listKeys(cache.probation))
+}
+
+func (s *CacheSuite) TestCache_ValueLeftWindow_ValueEnteredProbation() {
+	cache := s.newCache(100)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	assert.Equal(s.T(), []string{"key2"}, listKeys(cache.window))
+	assert.Equal(s.T(), []string{"key1"}, listKeys(cache.probation))
+}
+
+func (s *CacheSuite) TestCache_ProbationValueWasRead_ValueWasPromoted() {
+	cache := s.newCache(100)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	_, exists := cache.Get("key1")
+	require.True(s.T(), exists)
+	assert.Empty(s.T(), listKeys(cache.probation))
+	assert.Equal(s.T(), []string{"key1"}, listKeys(cache.protected))
+}
+
+func (s *CacheSuite) TestCache_ProtectedIsFull_OldestProtectedValueWasDemoted() {
+	cache := s.newCache(4)
+	require.Equal(s.T(), 2, cache.protectedCap)
+
+	for i := range 4 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+	for i := range 3 {
+		_, exists := cache.Get(fmt.Sprintf("key%d", i))
+		require.True(s.T(), exists)
+	}
+
+	assert.Equal(s.T(), []string{"key1", "key2"}, listKeys(cache.protected))
+	assert.Equal(s.T(), []string{"key0"}, listKeys(cache.probation))
+}
+
+func (s *CacheSuite) TestCache_CandidateIsUsedRarely_CandidateWasRejected() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("hot", 1))
+	for range 5 {
+		cache.Get("hot")
+	}
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	_, exists := cache.Get("hot")
+	assert.True(s.T(), exists, "frequently used value should stay")
+	_, exists = cache.Get("key1")
+	assert.False(s.T(), exists, "rarely used candidate should be rejected")
+	_, exists = cache.Get("key2")
+	assert.True(s.T(), exists, "the newest value stays in the window")
+}
+
+func (s *CacheSuite) TestCache_CandidateIsUsedOften_VictimWasEvicted() {
+	cache := s.newCache(2)
+
+	require.NoError(s.T(), cache.Set("old", 1))
+	require.NoError(s.T(), cache.Set("key1", 1))
+	for range 5 {
+		cache.Get("key1")
+	}
+
+	require.NoError(s.T(), cache.Set("key2", 2))
+
+	_, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	_, exists = cache.Get("old")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_Scan_FrequentValuesSurvived() {
+	cache := s.newCache(100)
+
+	for round := range 3 {
+		for i := range 50 {
+			key := fmt.Sprintf("hot%d", i)
+			if _, ok := cache.Get(key); !ok {
+				require.NoError(s.T(), cache.Set(key, round))
+			}
+		}
+	}
+
+	for i := range 1000 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("scan%d", i), i))
+	}
+
+	hits := 0
+	for i := range 50 {
+		if _, ok := cache.Get(fmt.Sprintf("hot%d", i)); ok {
+			hits++
+		}
+	}
+	assert.GreaterOrEqual(s.T(), hits, 45)
+}
+
+func (s *CacheSuite) newCache(capacity int) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{
+		Capacity: capacity,
+		Hasher:   stringHasher(),
+	})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+func listKeys(l *list.List[*entry[string, int]]) []string {
+	var keys []string
+	for e := l.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.key)
+	}
+
+	return keys
+}
@@ -0,0 +1,39 @@
+package tinylfucache
+
+import (
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/list"
+)
+
+type segment int
+
+const (
+	windowSegment segment = iota + 1
+	probationSegment
+	protectedSegment
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	hash      uint64
+	expiredAt time.Time
+	segment   segment
+	element   *list.Element[*entry[K, V]]
+	expiry    *heap.Element[*entry[K, V]]
+}
+
+func newEntry[K comparable, V any](key K, value V, hash uint64, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
+		value:     value,
+		hash:      hash,
+		expiredAt: expiredAt,
+	}
+}
+
+func (e *entry[K, V]) expirationTime() time.Time {
+	return e.expiredAt
+}
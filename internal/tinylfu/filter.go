@@ -0,0 +1,53 @@
+package tinylfucache
+
+// admissionFilter is the TinyLFU frequency filter. The first access of a
+// hash is recorded in the doorkeeper only, the next ones in the sketch.
+// After sampleSize recorded accesses the sketch is halved and the
+// doorkeeper is cleared, so the filter follows changes of the workload.
+type admissionFilter struct {
+	sketch     *countMinSketch
+	doorkeeper *bloomFilter
+	additions  int
+	sampleSize int
+}
+
+func newAdmissionFilter(capacity int) *admissionFilter {
+	return &admissionFilter{
+		sketch:     newCountMinSketch(capacity),
+		doorkeeper: newBloomFilter(capacity * 8),
+		sampleSize: 10 * capacity,
+	}
+}
+
+func (f *admissionFilter) Record(hash uint64) {
+	if f.doorkeeper.Add(hash) {
+		f.sketch.Increment(hash)
+	}
+
+	f.additions++
+	if f.additions >= f.sampleSize {
+		f.reset()
+	}
+}
+
+// Estimate returns the estimated number of recent accesses of the hash.
+func (f *admissionFilter) Estimate(hash uint64) int {
+	estimate := f.sketch.Estimate(hash)
+	if f.doorkeeper.Contains(hash) {
+		estimate++
+	}
+
+	return estimate
+}
+
+func (f *admissionFilter) Clear() {
+	f.sketch.Clear()
+	f.doorkeeper.Clear()
+	f.additions = 0
+}
+
+func (f *admissionFilter) reset() {
+	f.sketch.Halve()
+	f.doorkeeper.Clear()
+	f.additions /= 2
+}
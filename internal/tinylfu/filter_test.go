@@ -0,0 +1,35 @@
+package tinylfucache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmissionFilter(t *testing.T) {
+	t.Run("First access is kept by the doorkeeper", func(t *testing.T) {
+		f := newAdmissionFilter(100)
+
+		f.Record(42)
+		assert.Equal(t, 1, f.Estimate(42))
+		assert.Equal(t, 0, f.sketch.Estimate(42))
+
+		f.Record(42)
+		assert.Equal(t, 2, f.Estimate(42))
+	})
+
+	t.Run("Filter is reset after sample size accesses", func(t *testing.T) {
+		f := newAdmissionFilter(10)
+		for range 8 {
+			f.Record(42)
+		}
+		assert.Equal(t, 8, f.Estimate(42))
+
+		for i := range f.sampleSize - 8 {
+			f.Record(uint64(1000 + i))
+		}
+
+		assert.Equal(t, 3, f.Estimate(42), "sketch counter should be halved and doorkeeper cleared")
+		assert.Equal(t, f.sampleSize/2, f.additions)
+	})
+}
@@ -0,0 +1,93 @@
+package tinylfucache
+
+import (
+	"math/bits"
+)
+
+const (
+	sketchDepth = 4
+	// maxCount is the largest value of a 4-bit counter.
+	maxCount = 15
+	// resetMask clears the highest bit of every 4-bit counter after a
+	// shift, so halving does not leak bits between counters.
+	resetMask = 0x7777777777777777
+)
+
+var sketchSeeds = [sketchDepth]uint64{
+	0xc3a5c85c97cb3127,
+	0xb492b66fbe98f273,
+	0x9ae16a3b2f90404f,
+	0xcbf29ce484222325,
+}
+
+// countMinSketch estimates how often hashes were added. It has sketchDepth
+// rows of 4-bit counters packed 16 per word. The estimate of a hash is the
+// minimum of its counters, so collisions only make it larger.
+type countMinSketch struct {
+	rows  [sketchDepth][]uint64
+	shift uint
+}
+
+func newCountMinSketch(width int) *countMinSketch {
+	width = max(nextPowerOfTwo(width), 16)
+
+	s := countMinSketch{
+		shift: uint(64 - bits.TrailingZeros64(uint64(width))),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint64, width/16)
+	}
+
+	return &s
+}
+
+// Increment increments the counters of the hash which are not saturated.
+func (s *countMinSketch) Increment(hash uint64) {
+	for i := range s.rows {
+		word, offset := s.position(hash, i)
+		if (s.rows[i][word]>>offset)&maxCount < maxCount {
+			s.rows[i][word] += 1 << offset
+		}
+	}
+}
+
+func (s *countMinSketch) Estimate(hash uint64) int {
+	estimate := maxCount
+	for i := range s.rows {
+		word, offset := s.position(hash, i)
+		estimate = min(estimate, int((s.rows[i][word]>>offset)&maxCount))
+	}
+
+	return estimate
+}
+
+// Halve divides all counters by two, so that the sketch forgets old
+// accesses.
+func (s *countMinSketch) Halve() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] = (s.rows[i][j] >> 1) & resetMask
+		}
+	}
+}
+
+func (s *countMinSketch) Clear() {
+	for i := range s.rows {
+		clear(s.rows[i])
+	}
+}
+
+// position returns the word and the bit offset of the counter of the hash
+// in the i-th row.
+func (s *countMinSketch) position(hash uint64, i int) (int, uint) {
+	index := ((hash ^ sketchSeeds[i]) * 0x9e3779b97f4a7c15) >> s.shift
+	return int(index >> 4), uint(index&15) * 4
+}
+
+func nextPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+
+	return 1 << bits.Len(uint(n-1))
+}
@@ -0,0 +1,66 @@
+package tinylfucache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	t.Run("Estimate counts increments", func(t *testing.T) {
+		s := newCountMinSketch(64)
+		for range 5 {
+			s.Increment(42)
+		}
+		s.Increment(7)
+
+		assert.Equal(t, 5, s.Estimate(42))
+		assert.Equal(t, 1, s.Estimate(7))
+		assert.Equal(t, 0, s.Estimate(100))
+	})
+
+	t.Run("Counters saturate at 15", func(t *testing.T) {
+		s := newCountMinSketch(64)
+		for range 100 {
+			s.Increment(42)
+		}
+
+		assert.Equal(t, maxCount, s.Estimate(42))
+	})
+
+	t.Run("Halve divides counters by two", func(t *testing.T) {
+		s := newCountMinSketch(64)
+		for range 15 {
+			s.Increment(42)
+		}
+		for range 4 {
+			s.Increment(7)
+		}
+
+		s.Halve()
+		assert.Equal(t, 7, s.Estimate(42))
+		assert.Equal(t, 2, s.Estimate(7))
+	})
+
+	t.Run("Clear resets counters", func(t *testing.T) {
+		s := newCountMinSketch(64)
+		s.Increment(42)
+
+		s.Clear()
+		assert.Equal(t, 0, s.Estimate(42))
+	})
+
+	t.Run("Width is rounded to a power of two", func(t *testing.T) {
+		s := newCountMinSketch(100)
+
+		assert.Len(t, s.rows[0], 128/16)
+	})
+}
+
+func TestNextPowerOfTwo(t *testing.T) {
+	assert.Equal(t, 1, nextPowerOfTwo(0))
+	assert.Equal(t, 1, nextPowerOfTwo(1))
+	assert.Equal(t, 2, nextPowerOfTwo(2))
+	assert.Equal(t, 4, nextPowerOfTwo(3))
+	assert.Equal(t, 1024, nextPowerOfTwo(1000))
+}
@@ -3,54 +3,318 @@ package ttlcache
 import (
 	"sync"
 	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
 )
 
 type Cache[K comparable, V any] struct {
-	data map[K]entry[V]
-	ttl  time.Duration
-	mu   sync.Mutex
+	data             map[K]*entry[K, V]
+	queue            *heap.Heap[*entry[K, V]]
+	capacity         int
+	maxBytes         int64
+	bytes            int64
+	sizer            func(key K, value V) int64
+	rejectOnOverflow bool
+	ttl              time.Duration
+	janitor          *janitor.Janitor
+	stats            *lifecycle.Recorder[K, V]
+	mu               sync.RWMutex
 }
 
-func NewCache[K comparable, V any](params CacheInitParam) (*Cache[K, V], error) {
+func NewCache[K comparable, V any](params CacheInitParam[K, V]) (*Cache[K, V], error) {
 	if params.TTL <= 0 {
 		return nil, ErrIllegalTTL
 	}
 
+	if params.Capacity < 0 {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.MaxBytes < 0 {
+		return nil, ErrIllegalMaxBytes
+	}
+
+	if params.MaxBytes > 0 && params.Sizer == nil {
+		return nil, ErrSizerRequired
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
 	cache := Cache[K, V]{
-		data: make(map[K]entry[V], params.Capacity),
-		ttl:  params.TTL,
+		data:             make(map[K]*entry[K, V], params.Capacity),
+		queue:            heap.New((*entry[K, V]).expiresBefore),
+		capacity:         params.Capacity,
+		maxBytes:         params.MaxBytes,
+		sizer:            params.Sizer,
+		rejectOnOverflow: params.RejectOnOverflow,
+		ttl:              params.TTL,
+		stats:            lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
 	}
 
 	return &cache, nil
 }
 
+// Get returns the value stored by the key. A hit on a live entry changes
+// nothing in the cache, so concurrent hits share a read lock. An expired
+// entry is removed under the write lock.
 func (c *Cache[K, T]) Get(key K) (T, bool) {
+	c.mu.RLock()
+	v, ok := c.data[key]
+	if ok && !lifecycle.IsExpired(v.expiredAt) {
+		value := v.value
+		c.mu.RUnlock()
+
+		c.stats.Hit()
+		return value, true
+	}
+	c.mu.RUnlock()
+
+	if ok {
+		c.removeExpired(key)
+	}
+
+	c.stats.Miss()
+	return c.getZeroValue(), false
+}
+
+// removeExpired removes the entry by the key if it is still expired once
+// the write lock is taken. The entry may have been replaced or removed
+// after the read lock was released.
+func (c *Cache[K, V]) removeExpired(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+// Set stores the value by the key. When the cache is full, the entry which
+// expires first is evicted, or ErrCacheIsFull is returned if the cache was
+// created with RejectOnOverflow.
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, time.Now().Add(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	v, ok := c.data[key]
 	if !ok {
-		return c.getZeroValue(), false
+		return false
 	}
 
-	if !v.expiredAt.IsZero() && time.Now().After(v.expiredAt) {
-		delete(c.data, key)
-		return c.getZeroValue(), false
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
 	}
 
-	return v.value, true
+	c.removeEntry(v, removal.Deleted)
+	return true
 }
 
-func (c *Cache[K, V]) Set(key K, value V) error {
-	item := newEntry[V](value, c.ttl)
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.data)
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range c.data {
+		c.stats.Removal(v.key, v.value, removal.Deleted)
+	}
+
+	clear(c.data)
+	c.queue.Clear()
+	c.bytes = 0
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stats.Snapshot(len(c.data))
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	size := c.sizeOf(key, value)
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return ErrEntryTooLarge
+	}
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.data[key] = item
+
+	v, ok := c.data[key]
+	if ok && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	var err error
+	if ok {
+		err = c.updateEntry(v, value, size, expiredAt)
+	} else {
+		err = c.addNewEntry(key, value, size, expiredAt)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, size int64, expiredAt time.Time) error {
+	// The entry is detached from the queue while making room, so it can not
+	// be evicted to make room for itself.
+	c.queue.Remove(entry.element)
+	c.bytes -= entry.size
+
+	if err := c.makeRoom(0, size); err != nil {
+		c.bytes += entry.size
+		entry.element = c.queue.Push(entry)
+		return err
+	}
+
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	c.bytes += size
+	entry.value = value
+	entry.size = size
+	entry.expiredAt = expiredAt
+	entry.element = c.queue.Push(entry)
+
+	return nil
+}
+
+func (c *Cache[K, V]) addNewEntry(key K, value V, size int64, expiredAt time.Time) error {
+	if err := c.makeRoom(1, size); err != nil {
+		return err
+	}
+
+	entry := newEntry(key, value, expiredAt)
+	entry.size = size
+
+	c.data[key] = entry
+	c.bytes += size
+	entry.element = c.queue.Push(entry)
+
+	return nil
+}
+
+// makeRoom removes expired entries and then evicts the entries which expire
+// first until count more entries and bytes more bytes fit into the cache.
+// With RejectOnOverflow nothing alive is evicted and ErrCacheIsFull is
+// returned instead.
+func (c *Cache[K, V]) makeRoom(count int, bytes int64) error {
+	if !c.isOverflowed(count, bytes) {
+		return nil
+	}
+
+	c.removeExpiredEntries()
+
+	for c.isOverflowed(count, bytes) {
+		if c.rejectOnOverflow {
+			return ErrCacheIsFull
+		}
+
+		first := c.queue.Peek()
+		if first == nil {
+			return nil
+		}
+
+		c.removeEntry(first.Value, removal.Capacity)
+	}
 
 	return nil
 }
 
+func (c *Cache[K, V]) isOverflowed(count int, bytes int64) bool {
+	if c.capacity > 0 && len(c.data)+count > c.capacity {
+		return true
+	}
+
+	return c.maxBytes > 0 && c.bytes+bytes > c.maxBytes
+}
+
+func (c *Cache[K, V]) sizeOf(key K, value V) int64 {
+	if c.sizer == nil {
+		return 0
+	}
+
+	return c.sizer(key, value)
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.bytes -= entry.size
+	c.queue.Remove(entry.element)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeExpiredEntries()
+}
+
+func (c *Cache[K, V]) removeExpiredEntries() {
+	for {
+		first := c.queue.Peek()
+		if first == nil || !lifecycle.IsExpired(first.Value.expiredAt) {
+			return
+		}
+
+		c.removeEntry(first.Value, removal.Expired)
+	}
+}
+
 func (c *Cache[K, T]) getZeroValue() T {
 	var zeroValue T
 	return zeroValue
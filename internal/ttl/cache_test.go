@@ -1,9 +1,14 @@
 package ttlcache
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -19,7 +24,7 @@ func TestCacheSuite(t *testing.T) {
 }
 
 func (s *CacheSuite) TestNewCache_WithoutParams_ReturnErr() {
-	initParams := CacheInitParam{}
+	initParams := CacheInitParam[string, int]{}
 
 	cache, err := NewCache[string, int](initParams)
 	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
@@ -27,7 +32,7 @@ func (s *CacheSuite) TestNewCache_WithoutParams_ReturnErr() {
 }
 
 func (s *CacheSuite) TestNewCache_WithAllParams_ReturnCache() {
-	initParams := CacheInitParam{
+	initParams := CacheInitParam[string, int]{
 		TTL:      100 * time.Millisecond,
 		Capacity: 100,
 	}
@@ -42,7 +47,7 @@ func (s *CacheSuite) TestCache_CacheForInt_StoreInCache() {
 	value := 100500
 	ttl := 100 * time.Millisecond
 
-	initParams := CacheInitParam{
+	initParams := CacheInitParam[string, int]{
 		TTL: ttl,
 	}
 	cache, err := NewCache[string, int](initParams)
@@ -76,7 +81,7 @@ func (s *CacheSuite) TestCache_CacheForStruct_StoreInCache() {
 	}
 	ttl := 100 * time.Millisecond
 
-	initParams := CacheInitParam{
+	initParams := CacheInitParam[string, StructForCache]{
 		TTL: ttl,
 	}
 	cache, err := NewCache[string, StructForCache](initParams)
@@ -102,7 +107,7 @@ func (s *CacheSuite) TestCache_GetByNotExistedKey_ReturnDefaultValue() {
 	value := 100500
 	ttl := 100 * time.Millisecond
 
-	initParams := CacheInitParam{
+	initParams := CacheInitParam[string, int]{
 		TTL: ttl,
 	}
 	cache, err := NewCache[string, int](initParams)
@@ -120,3 +125,572 @@ func (s *CacheSuite) TestCache_GetByNotExistedKey_ReturnDefaultValue() {
 	assert.False(s.T(), exists)
 	assert.Empty(s.T(), storedValue)
 }
+
+func (s *CacheSuite) TestCache_DeleteExistedKey_ValueWasRemoved() {
+	initParams := CacheInitParam[string, int]{
+		TTL: 100 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	key := "key"
+	err = cache.Set(key, 100500)
+	require.NoError(s.T(), err)
+
+	deleted := cache.Delete(key)
+	assert.True(s.T(), deleted)
+	assert.Equal(s.T(), 0, cache.Len())
+
+	storedValue, exists := cache.Get(key)
+	assert.False(s.T(), exists)
+	assert.Empty(s.T(), storedValue)
+}
+
+func (s *CacheSuite) TestCache_DeleteNotExistedKey_ReturnFalse() {
+	initParams := CacheInitParam[string, int]{
+		TTL: 100 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	deleted := cache.Delete("not_existed_key")
+	assert.False(s.T(), deleted)
+}
+
+func (s *CacheSuite) TestCache_Clear_AllValuesWereRemoved() {
+	initParams := CacheInitParam[string, int]{
+		TTL: 100 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 100501)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 2, cache.Len())
+
+	cache.Clear()
+	assert.Equal(s.T(), 0, cache.Len())
+
+	storedValue, exists := cache.Get("key1")
+	assert.False(s.T(), exists)
+	assert.Empty(s.T(), storedValue)
+}
+
+func (s *CacheSuite) TestNewCache_IllegalCleanupInterval_ReturnErr() {
+	initParams := CacheInitParam[string, int]{
+		TTL:             100 * time.Millisecond,
+		CleanupInterval: -time.Second,
+	}
+
+	cache, err := NewCache[string, int](initParams)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+	assert.Nil(s.T(), cache)
+}
+
+func (s *CacheSuite) TestCache_WithCleanupInterval_ExpiredValueWasRemovedWithoutAccess() {
+	initParams := CacheInitParam[string, int]{
+		TTL:             50 * time.Millisecond,
+		CleanupInterval: 10 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+	defer cache.Close()
+
+	err = cache.Set("key", 100500)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, cache.Len())
+
+	assert.Eventually(s.T(), func() bool {
+		return cache.Len() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *CacheSuite) TestCache_CloseTwice_NotPanics() {
+	initParams := CacheInitParam[string, int]{
+		TTL:             100 * time.Millisecond,
+		CleanupInterval: 10 * time.Millisecond,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	cache.Close()
+	assert.NotPanics(s.T(), cache.Close)
+}
+
+func (s *CacheSuite) TestNewCache_IllegalCapacity_ReturnErr() {
+	initParams := CacheInitParam[string, int]{
+		TTL:      100 * time.Millisecond,
+		Capacity: -1,
+	}
+
+	cache, err := NewCache[string, int](initParams)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+	assert.Nil(s.T(), cache)
+}
+
+func (s *CacheSuite) TestCache_NotEnoughCapacity_ValueWhichExpiresFirstWasEvicted() {
+	initParams := CacheInitParam[string, int]{
+		TTL:      time.Minute,
+		Capacity: 2,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 100501)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100502)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	err = cache.Set("key3", 100503)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	_, exists := cache.Get("key2")
+	assert.False(s.T(), exists)
+
+	storedValue, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 100502, storedValue)
+
+	storedValue, exists = cache.Get("key3")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 100503, storedValue)
+}
+
+func (s *CacheSuite) TestCache_NotEnoughCapacityWithRejectOnOverflow_ReturnErr() {
+	initParams := CacheInitParam[string, int]{
+		TTL:              time.Minute,
+		Capacity:         1,
+		RejectOnOverflow: true,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key2", 100501)
+	assert.ErrorIs(s.T(), err, ErrCacheIsFull)
+
+	err = cache.Set("key1", 100502)
+	assert.NoError(s.T(), err)
+
+	storedValue, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 100502, storedValue)
+
+	_, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_FullOfExpiredValuesWithRejectOnOverflow_ExpiredValueWasReplaced() {
+	ttl := 50 * time.Millisecond
+	initParams := CacheInitParam[string, int]{
+		TTL:              ttl,
+		Capacity:         1,
+		RejectOnOverflow: true,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	err = cache.Set("key2", 100501)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, cache.Len())
+
+	storedValue, exists := cache.Get("key2")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 100501, storedValue)
+}
+
+func (s *CacheSuite) TestCache_SetWithTTL_ValueExpiredByOwnTTL() {
+	initParams := CacheInitParam[string, int]{
+		TTL: time.Minute,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	ttl := 50 * time.Millisecond
+	err = cache.SetWithTTL("key", 100500, ttl)
+	require.NoError(s.T(), err)
+
+	storedValue, exists := cache.Get("key")
+	require.True(s.T(), exists)
+	assert.Equal(s.T(), 100500, storedValue)
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	_, exists = cache.Get("key")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_SetWithZeroTTL_ValueNeverExpires() {
+	ttl := 50 * time.Millisecond
+	initParams := CacheInitParam[string, int]{
+		TTL:      ttl,
+		Capacity: 2,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	err = cache.SetWithTTL("eternal", 100500, 0)
+	require.NoError(s.T(), err)
+	err = cache.Set("key1", 100501)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 100502)
+	require.NoError(s.T(), err)
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	storedValue, exists := cache.Get("eternal")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 100500, storedValue)
+}
+
+func (s *CacheSuite) TestCache_SetWithNegativeTTL_ReturnErr() {
+	initParams := CacheInitParam[string, int]{
+		TTL: time.Minute,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	err = cache.SetWithTTL("key", 100500, -time.Second)
+	assert.ErrorIs(s.T(), err, ErrIllegalEntryTTL)
+	assert.Equal(s.T(), 0, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_SetWithDeadline_ValueExpiredAtDeadline() {
+	initParams := CacheInitParam[string, int]{
+		TTL: time.Minute,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	err = cache.SetWithDeadline("key", 100500, deadline)
+	require.NoError(s.T(), err)
+
+	storedValue, exists := cache.Get("key")
+	require.True(s.T(), exists)
+	assert.Equal(s.T(), 100500, storedValue)
+
+	time.Sleep(time.Until(deadline) + 20*time.Millisecond)
+
+	_, exists = cache.Get("key")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestCache_SetWithPastDeadline_ReturnErr() {
+	initParams := CacheInitParam[string, int]{
+		TTL: time.Minute,
+	}
+	cache, err := NewCache[string, int](initParams)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	err = cache.SetWithDeadline("key", 100500, time.Now().Add(-time.Second))
+	assert.ErrorIs(s.T(), err, ErrIllegalDeadline)
+	assert.Equal(s.T(), 0, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_WithOnEvict_CallbackReceivedRemovalReasons() {
+	recorder := &evictionRecorder{}
+	ttl := 50 * time.Millisecond
+	params := CacheInitParam[string, int]{
+		TTL:      ttl,
+		Capacity: 1,
+		OnEvict:  recorder.record,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	err = cache.Set("key1", 100501)
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", 100502)
+	require.NoError(s.T(), err)
+	deleted := cache.Delete("key2")
+	require.True(s.T(), deleted)
+
+	err = cache.Set("key3", 100503)
+	require.NoError(s.T(), err)
+	time.Sleep(ttl + 20*time.Millisecond)
+	_, exists := cache.Get("key3")
+	require.False(s.T(), exists)
+
+	err = cache.Set("key4", 100504)
+	require.NoError(s.T(), err)
+	cache.Clear()
+
+	expected := []eviction{
+		{key: "key1", value: 100500, reason: removal.Replaced},
+		{key: "key1", value: 100501, reason: removal.Capacity},
+		{key: "key2", value: 100502, reason: removal.Deleted},
+		{key: "key3", value: 100503, reason: removal.Expired},
+		{key: "key4", value: 100504, reason: removal.Deleted},
+	}
+	assert.Equal(s.T(), expected, recorder.evictions)
+}
+
+type eviction struct {
+	key    string
+	value  int
+	reason removal.Reason
+}
+
+type evictionRecorder struct {
+	evictions []eviction
+}
+
+func (r *evictionRecorder) record(key string, value int, reason removal.Reason) {
+	r.evictions = append(r.evictions, eviction{key: key, value: value, reason: reason})
+}
+
+func (s *CacheSuite) TestCache_Stats_CountersWereCollected() {
+	ttl := 50 * time.Millisecond
+	params := CacheInitParam[string, int]{
+		TTL:      ttl,
+		Capacity: 1,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", 100500)
+	require.NoError(s.T(), err)
+	_, exists := cache.Get("key1")
+	require.True(s.T(), exists)
+	_, exists = cache.Get("not_existed_key")
+	require.False(s.T(), exists)
+
+	err = cache.Set("key2", 100501)
+	require.NoError(s.T(), err)
+	time.Sleep(ttl + 20*time.Millisecond)
+	_, exists = cache.Get("key2")
+	require.False(s.T(), exists)
+
+	expected := stats.Stats{
+		Hits:        1,
+		Misses:      2,
+		Sets:        2,
+		Evictions:   1,
+		Expirations: 1,
+		Size:        0,
+	}
+	assert.Equal(s.T(), expected, cache.Stats())
+	assert.InDelta(s.T(), 1.0/3.0, cache.Stats().HitRatio(), 0.001)
+
+	cache.ResetStats()
+	assert.Equal(s.T(), stats.Stats{}, cache.Stats())
+}
+
+func (s *CacheSuite) TestNewCache_IllegalMaxBytes_ReturnError() {
+	params := CacheInitParam[string, string]{
+		TTL:      time.Minute,
+		MaxBytes: -1,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalMaxBytes)
+}
+
+func (s *CacheSuite) TestNewCache_MaxBytesWithoutSizer_ReturnError() {
+	params := CacheInitParam[string, string]{
+		TTL:      time.Minute,
+		MaxBytes: 100,
+	}
+	cache, err := NewCache[string, string](params)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrSizerRequired)
+}
+
+func (s *CacheSuite) TestCache_NotEnoughBytes_ValuesWereEvictedUntilNewValueFits() {
+	params := CacheInitParam[string, string]{
+		TTL:      time.Minute,
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "1234")
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", "1234")
+	require.NoError(s.T(), err)
+	err = cache.Set("key3", "12")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 3, cache.Len())
+
+	err = cache.Set("key4", "12345678")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, cache.Len())
+
+	_, exists := cache.Get("key1")
+	assert.False(s.T(), exists)
+	_, exists = cache.Get("key2")
+	assert.False(s.T(), exists)
+
+	value, exists := cache.Get("key3")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "12", value)
+
+	value, exists = cache.Get("key4")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "12345678", value)
+}
+
+func (s *CacheSuite) TestCache_UpdatedValueGrew_OtherValuesWereEvicted() {
+	params := CacheInitParam[string, string]{
+		TTL:      time.Minute,
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "1234")
+	require.NoError(s.T(), err)
+	err = cache.Set("key2", "1234")
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "123456789")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, cache.Len())
+
+	value, exists := cache.Get("key1")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "123456789", value)
+}
+
+func (s *CacheSuite) TestCache_ValueLargerThanMaxBytes_ReturnError() {
+	params := CacheInitParam[string, string]{
+		TTL:      time.Minute,
+		MaxBytes: 10,
+		Sizer:    stringSizer,
+	}
+	cache, err := NewCache[string, string](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key1", "1234")
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key2", "12345678901")
+	assert.ErrorIs(s.T(), err, ErrEntryTooLarge)
+	assert.Equal(s.T(), 1, cache.Len())
+}
+
+func stringSizer(_ string, value string) int64 {
+	return int64(len(value))
+}
+
+func (s *CacheSuite) TestCache_ConcurrentGetOfExpiredValue_ValueWasRemovedOnce() {
+	recorder := &evictionRecorder{}
+	params := CacheInitParam[string, int]{
+		TTL:     time.Minute,
+		OnEvict: recorder.record,
+	}
+	cache, err := NewCache[string, int](params)
+	require.NotNil(s.T(), cache)
+	require.NoError(s.T(), err)
+
+	err = cache.SetWithTTL("key1", 1, 10*time.Millisecond)
+	require.NoError(s.T(), err)
+	time.Sleep(20 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, exists := cache.Get("key1")
+			assert.False(s.T(), exists)
+		}()
+	}
+	wg.Wait()
+
+	assert.Zero(s.T(), cache.Len())
+	assert.Equal(s.T(), []eviction{{key: "key1", value: 1, reason: removal.Expired}}, recorder.evictions)
+	assert.Equal(s.T(), uint64(8), cache.Stats().Misses)
+}
+
+func BenchmarkCache_ParallelGet(b *testing.B) {
+	const capacity = 10_000
+
+	for _, goroutines := range []int{1, 8, 64} {
+		b.Run(fmt.Sprintf("goroutines=%d", goroutines), func(b *testing.B) {
+			params := CacheInitParam[int, int]{
+				Capacity: capacity,
+				TTL:      time.Hour,
+			}
+			cache, err := NewCache[int, int](params)
+			require.NoError(b, err)
+
+			for i := 0; i < capacity; i++ {
+				err = cache.Set(i, i)
+				require.NoError(b, err)
+			}
+
+			b.ResetTimer()
+			cachetest.RunParallel(b, goroutines, func(i int) {
+				cache.Get(i % capacity)
+			})
+		})
+	}
+}
+
+func BenchmarkCache_ParallelGetAndSet(b *testing.B) {
+	const capacity = 10_000
+
+	for _, goroutines := range []int{1, 8, 64} {
+		b.Run(fmt.Sprintf("goroutines=%d", goroutines), func(b *testing.B) {
+			params := CacheInitParam[int, int]{
+				Capacity: capacity,
+				TTL:      time.Hour,
+			}
+			cache, err := NewCache[int, int](params)
+			require.NoError(b, err)
+
+			for i := 0; i < capacity; i++ {
+				err = cache.Set(i, i)
+				require.NoError(b, err)
+			}
+
+			b.ResetTimer()
+			cachetest.RunParallel(b, goroutines, func(i int) {
+				if i%10 == 0 {
+					_ = cache.Set(i%capacity, i)
+					return
+				}
+				cache.Get(i % capacity)
+			})
+		})
+	}
+}
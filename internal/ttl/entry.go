@@ -2,16 +2,29 @@ package ttlcache
 
 import (
 	"time"
+
+	"github.com/conacry/inmem-cache/internal/heap"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
 )
 
-type entry[T any] struct {
-	value     T
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
 	expiredAt time.Time
+	size      int64
+	element   *heap.Element[*entry[K, V]]
 }
 
-func newEntry[T any](value T, ttl time.Duration) entry[T] {
-	return entry[T]{
+func newEntry[K comparable, V any](key K, value V, expiredAt time.Time) *entry[K, V] {
+	return &entry[K, V]{
+		key:       key,
 		value:     value,
-		expiredAt: time.Now().Add(ttl),
+		expiredAt: expiredAt,
 	}
 }
+
+// expiresBefore reports whether e expires earlier than other. Entries
+// without expiration time are considered to expire last.
+func (e *entry[K, V]) expiresBefore(other *entry[K, V]) bool {
+	return lifecycle.ExpiresBefore(e.expiredAt, other.expiredAt)
+}
@@ -9,12 +9,15 @@ import (
 
 func TestNewEntry(t *testing.T) {
 	t.Run("Create new entry", func(t *testing.T) {
+		key := "key"
 		value := "value"
-		ttl := 10 * time.Second
-		entry := newEntry(value, ttl)
+		expiredAt := time.Now().Add(10 * time.Second)
+		entry := newEntry(key, value, expiredAt)
 
 		assert.NotEmpty(t, entry)
+		assert.Equal(t, key, entry.key)
 		assert.Equal(t, value, entry.value)
-		assert.NotEqual(t, time.Time{}, entry.expiredAt)
+		assert.Equal(t, expiredAt, entry.expiredAt)
+		assert.Nil(t, entry.element)
 	})
 }
@@ -2,8 +2,18 @@ package ttlcache
 
 import (
 	"errors"
+
+	"github.com/conacry/inmem-cache/internal/lifecycle"
 )
 
 var (
-	ErrIllegalTTL = errors.New("ttl should be greater than 0")
+	ErrIllegalTTL             = errors.New("ttl should be greater than 0")
+	ErrIllegalCapacity        = errors.New("capacity should not be negative")
+	ErrIllegalCleanupInterval = errors.New("cleanup interval should not be negative")
+	ErrIllegalEntryTTL        = lifecycle.ErrIllegalEntryTTL
+	ErrIllegalDeadline        = lifecycle.ErrIllegalDeadline
+	ErrIllegalMaxBytes        = errors.New("max bytes should not be negative")
+	ErrSizerRequired          = errors.New("sizer is required when max bytes is set")
+	ErrEntryTooLarge          = errors.New("entry size exceeds max bytes")
+	ErrCacheIsFull            = errors.New("cache is full")
 )
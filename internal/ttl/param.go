@@ -2,9 +2,23 @@ package ttlcache
 
 import (
 	"time"
+
+	"github.com/conacry/inmem-cache/internal/removal"
 )
 
-type CacheInitParam struct {
+type CacheInitParam[K comparable, V any] struct {
+	// Capacity limits the number of entries, 0 means the cache is unbounded.
 	Capacity int
+	// MaxBytes limits the total size of entries measured by Sizer, 0 means
+	// there is no limit.
+	MaxBytes int64
+	Sizer    func(key K, value V) int64
 	TTL      time.Duration
+	// RejectOnOverflow makes Set return ErrCacheIsFull instead of evicting
+	// the entry which expires first when the cache is full.
+	RejectOnOverflow bool
+	CleanupInterval  time.Duration
+	// OnEvict is called for every entry leaving the cache. It runs while
+	// the cache lock is held, so it must not call the cache.
+	OnEvict func(key K, value V, reason removal.Reason)
 }
@@ -0,0 +1,294 @@
+package twoqcache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/janitor"
+	"github.com/conacry/inmem-cache/internal/lifecycle"
+	"github.com/conacry/inmem-cache/internal/list"
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
+// Cache is a 2Q cache. New entries are added to the FIFO queue A1in, and
+// hits there do not change the order, so entries used a few times in a row
+// are still treated as seen once. Entries evicted from A1in leave their
+// keys in the ghost queue A1out. Only an entry set again while its key is
+// in A1out gets into the LRU queue Am, so entries seen once never evict
+// the hot entries of Am.
+//
+// A1in is kept at a quarter of the capacity unless Am is empty, and A1out
+// remembers up to half of the capacity keys.
+type Cache[K comparable, V any] struct {
+	// data holds both resident and ghost entries.
+	data     map[K]*entry[K, V]
+	a1in     *list.List[*entry[K, V]]
+	a1out    *list.List[*entry[K, V]]
+	am       *list.List[*entry[K, V]]
+	expiry   *lifecycle.ExpiryQueue[*entry[K, V]]
+	capacity int
+	kin      int
+	kout     int
+	ttl      time.Duration
+	janitor  *janitor.Janitor
+	stats    *lifecycle.Recorder[K, V]
+	mu       sync.Mutex
+}
+
+func NewCache[K comparable, V any](params InitParam[K, V]) (*Cache[K, V], error) {
+	if params.Capacity <= 0 {
+		return nil, ErrIllegalCapacity
+	}
+
+	if params.TTL < 0 {
+		return nil, ErrIllegalTTL
+	}
+
+	if params.CleanupInterval < 0 {
+		return nil, ErrIllegalCleanupInterval
+	}
+
+	cache := Cache[K, V]{
+		data:     make(map[K]*entry[K, V], params.Capacity),
+		a1in:     list.New[*entry[K, V]](),
+		a1out:    list.New[*entry[K, V]](),
+		am:       list.New[*entry[K, V]](),
+		expiry:   lifecycle.NewExpiryQueue((*entry[K, V]).expirationTime),
+		capacity: params.Capacity,
+		kin:      max(params.Capacity/4, 1),
+		kout:     max(params.Capacity/2, 1),
+		ttl:      params.TTL,
+		stats:    lifecycle.NewRecorder(params.OnEvict),
+	}
+
+	if params.CleanupInterval > 0 {
+		cache.janitor = janitor.New(params.CleanupInterval, cache.deleteExpired)
+	}
+
+	return &cache, nil
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok || !c.isResident(v) {
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		c.stats.Miss()
+		return c.getZeroValue(), false
+	}
+
+	c.am.MoveToBack(v.element)
+	c.stats.Hit()
+	return v.value, true
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set(key, value, lifecycle.ExpirationTime(c.ttl))
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	expiredAt, err := lifecycle.EntryExpirationTime(ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.set(key, value, expiredAt)
+}
+
+func (c *Cache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	if err := lifecycle.CheckDeadline(deadline); err != nil {
+		return err
+	}
+
+	return c.set(key, value, deadline)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if !ok || !c.isResident(v) {
+		return false
+	}
+
+	if lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		return false
+	}
+
+	c.removeEntry(v, removal.Deleted)
+	return true
+}
+
+// Len does not count remembered keys of evicted entries.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.residentLen()
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, l := range []*list.List[*entry[K, V]]{c.a1in, c.am} {
+		for e := l.Front(); e != nil; e = e.Next() {
+			c.stats.Removal(e.Value.key, e.Value.value, removal.Deleted)
+		}
+	}
+
+	clear(c.data)
+	c.a1in.Clear()
+	c.a1out.Clear()
+	c.am.Clear()
+	c.expiry.Clear()
+}
+
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats.Snapshot(c.residentLen())
+}
+
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.Reset()
+}
+
+func (c *Cache[K, V]) Close() {
+	if c.janitor != nil {
+		c.janitor.Stop()
+	}
+}
+
+func (c *Cache[K, V]) set(key K, value V, expiredAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.data[key]
+	if ok && c.isResident(v) && lifecycle.IsExpired(v.expiredAt) {
+		c.removeEntry(v, removal.Expired)
+		ok = false
+	}
+
+	switch {
+	case !ok:
+		c.addNewEntry(key, value, expiredAt)
+	case c.isResident(v):
+		c.updateEntry(v, value, expiredAt)
+	default:
+		c.restoreGhost(v, value, expiredAt)
+	}
+
+	c.stats.Set()
+	return nil
+}
+
+func (c *Cache[K, V]) updateEntry(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.stats.Removal(entry.key, entry.value, removal.Replaced)
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	c.expiry.Fix(entry.expiry)
+	c.am.MoveToBack(entry.element)
+}
+
+// restoreGhost stores the value of a key remembered in A1out. The key was
+// seen again after it had left A1in, so the entry goes to Am.
+func (c *Cache[K, V]) restoreGhost(entry *entry[K, V], value V, expiredAt time.Time) {
+	c.a1out.Remove(entry.element)
+
+	if c.residentLen() >= c.capacity {
+		c.reclaim()
+	}
+
+	entry.value = value
+	entry.expiredAt = expiredAt
+	entry.element = c.am.PushBack(entry)
+	entry.expiry = c.expiry.Push(entry)
+}
+
+func (c *Cache[K, V]) addNewEntry(key K, value V, expiredAt time.Time) {
+	if c.residentLen() >= c.capacity {
+		c.reclaim()
+	}
+
+	entry := newEntry(key, value, expiredAt)
+	entry.element = c.a1in.PushBack(entry)
+	entry.expiry = c.expiry.Push(entry)
+	c.data[key] = entry
+}
+
+// reclaim evicts the oldest entry of A1in to A1out if A1in is over its
+// size, and the least recently used entry of Am otherwise.
+func (c *Cache[K, V]) reclaim() {
+	if c.a1in.Len() > c.kin || c.am.Len() == 0 {
+		c.evictToGhost(c.a1in.Front().Value)
+		return
+	}
+
+	c.removeEntry(c.am.Front().Value, removal.Capacity)
+}
+
+func (c *Cache[K, V]) evictToGhost(entry *entry[K, V]) {
+	c.a1in.Remove(entry.element)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, removal.Capacity)
+
+	entry.value = c.getZeroValue()
+	entry.element = c.a1out.PushBack(entry)
+
+	if c.a1out.Len() > c.kout {
+		c.removeGhost(c.a1out.Front().Value)
+	}
+}
+
+func (c *Cache[K, V]) isResident(entry *entry[K, V]) bool {
+	return c.a1in.Contains(entry.element) || c.am.Contains(entry.element)
+}
+
+func (c *Cache[K, V]) residentLen() int {
+	return c.a1in.Len() + c.am.Len()
+}
+
+func (c *Cache[K, V]) removeEntry(entry *entry[K, V], reason removal.Reason) {
+	delete(c.data, entry.key)
+	c.a1in.Remove(entry.element)
+	c.am.Remove(entry.element)
+	c.expiry.Remove(entry.expiry)
+	c.stats.Removal(entry.key, entry.value, reason)
+}
+
+func (c *Cache[K, V]) removeGhost(entry *entry[K, V]) {
+	delete(c.data, entry.key)
+	c.a1out.Remove(entry.element)
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		v, ok := c.expiry.Expired()
+		if !ok {
+			return
+		}
+
+		c.removeEntry(v, removal.Expired)
+	}
+}
+
+func (c *Cache[K, T]) getZeroValue() T {
+	var zeroValue T
+	return zeroValue
+}
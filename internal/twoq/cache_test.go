@@ -0,0 +1,167 @@
+package twoqcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/conacry/inmem-cache/internal/cachetest"
+	"github.com/conacry/inmem-cache/internal/list"
+	lrucache "github.com/conacry/inmem-cache/internal/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) TestNewCache_IllegalParams_ReturnError() {
+	cache, err := NewCache[string, int](InitParam[string, int]{})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCapacity)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, TTL: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalTTL)
+
+	cache, err = NewCache[string, int](InitParam[string, int]{Capacity: 10, CleanupInterval: -time.Second})
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalCleanupInterval)
+}
+
+func (s *CacheSuite) TestCache_HitInA1in_OrderWasNotChanged() {
+	cache := s.newCache(8)
+
+	require.NoError(s.T(), cache.Set("key1", 1))
+	require.NoError(s.T(), cache.Set("key2", 2))
+	cache.Get("key1")
+
+	assert.Equal(s.T(), []string{"key1", "key2"}, listKeys(cache.a1in))
+	assert.Empty(s.T(), listKeys(cache.am))
+}
+
+func (s *CacheSuite) TestCache_A1inIsOverSize_OldestValueWasMovedToA1out() {
+	cache := s.newCache(4)
+
+	for i := range 5 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+
+	assert.Equal(s.T(), []string{"key0"}, listKeys(cache.a1out))
+	assert.Equal(s.T(), 4, cache.Len())
+
+	_, exists := cache.Get("key0")
+	assert.False(s.T(), exists, "ghost entry should not be returned")
+	assert.False(s.T(), cache.Delete("key0"))
+}
+
+func (s *CacheSuite) TestCache_SetKeyFromA1out_ValueWasAddedToAm() {
+	cache := s.newCache(4)
+
+	for i := range 5 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("key%d", i), i))
+	}
+	require.Equal(s.T(), []string{"key0"}, listKeys(cache.a1out))
+
+	require.NoError(s.T(), cache.Set("key0", 10))
+
+	assert.Equal(s.T(), []string{"key0"}, listKeys(cache.am))
+	assert.Equal(s.T(), []string{"key1"}, listKeys(cache.a1out), "A1in should give room for the value")
+	value, exists := cache.Get("key0")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), 10, value)
+	assert.Equal(s.T(), 4, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_HitInAm_ValueWasMovedToBack() {
+	cache := s.newCache(4)
+	s.fillAm(cache, "hot0", "hot1")
+	require.Equal(s.T(), []string{"hot0", "hot1"}, listKeys(cache.am))
+
+	cache.Get("hot0")
+
+	assert.Equal(s.T(), []string{"hot1", "hot0"}, listKeys(cache.am))
+}
+
+func (s *CacheSuite) TestCache_Scan_ValuesOfAmSurvived() {
+	cache := s.newCache(8)
+	s.fillAm(cache, "hot0", "hot1", "hot2", "hot3")
+
+	for i := range 100 {
+		require.NoError(s.T(), cache.Set(fmt.Sprintf("scan%d", i), i))
+	}
+
+	for i := range 4 {
+		value, exists := cache.Get(fmt.Sprintf("hot%d", i))
+		assert.True(s.T(), exists)
+		assert.Equal(s.T(), i, value)
+	}
+	assert.Equal(s.T(), 8, cache.Len())
+}
+
+func (s *CacheSuite) TestCache_ManyEvictions_A1outWasBounded() {
+	cache := s.newCache(10)
+
+	for i := range 1000 {
+		key := fmt.Sprintf("key%d", i%50)
+		require.NoError(s.T(), cache.Set(key, i))
+		if i%3 == 0 {
+			cache.Get(key)
+		}
+
+		require.LessOrEqual(s.T(), cache.residentLen(), 10)
+		require.LessOrEqual(s.T(), cache.a1out.Len(), 5)
+		require.Equal(s.T(), len(cache.data), cache.a1in.Len()+cache.a1out.Len()+cache.am.Len())
+	}
+}
+
+func (s *CacheSuite) newCache(capacity int) *Cache[string, int] {
+	cache, err := NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	return cache
+}
+
+// fillAm gets the keys into Am by setting them, pushing them out of A1in
+// and setting them again. The value of every key is its index.
+func (s *CacheSuite) fillAm(cache *Cache[string, int], keys ...string) {
+	for i, key := range keys {
+		require.NoError(s.T(), cache.Set(key, i))
+		cache.evictToGhost(cache.data[key])
+		require.NoError(s.T(), cache.Set(key, i))
+	}
+}
+
+func listKeys(l *list.List[*entry[string, int]]) []string {
+	var keys []string
+	for e := l.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.key)
+	}
+
+	return keys
+}
+
+// BenchmarkCache_HitRatio replays the same traces against 2Q and LRU and
+// reports the hit ratio of each.
+func BenchmarkCache_HitRatio(b *testing.B) {
+	const capacity = 1_000
+
+	policies := []cachetest.Policy{
+		{Name: "2q", NewCache: func() (cachetest.Cache, error) {
+			return NewCache[string, int](InitParam[string, int]{Capacity: capacity})
+		}},
+		{Name: "lru", NewCache: func() (cachetest.Cache, error) {
+			return lrucache.NewCache[string, int](lrucache.InitParam[string, int]{Capacity: capacity, TTL: time.Hour})
+		}},
+	}
+
+	cachetest.BenchmarkHitRatio(b, cachetest.DefaultTraces(), policies)
+}
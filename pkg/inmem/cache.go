@@ -2,18 +2,78 @@ package inmem
 
 import (
 	"fmt"
+	"time"
 
+	arccache "github.com/conacry/inmem-cache/internal/arc"
+	clockcache "github.com/conacry/inmem-cache/internal/clock"
+	clockprocache "github.com/conacry/inmem-cache/internal/clockpro"
+	gdsfcache "github.com/conacry/inmem-cache/internal/gdsf"
 	lfucache "github.com/conacry/inmem-cache/internal/lfu"
+	lirscache "github.com/conacry/inmem-cache/internal/lirs"
 	lrucache "github.com/conacry/inmem-cache/internal/lru"
+	lrukcache "github.com/conacry/inmem-cache/internal/lruk"
+	s3fifocache "github.com/conacry/inmem-cache/internal/s3fifo"
+	sievecache "github.com/conacry/inmem-cache/internal/sieve"
+	slrucache "github.com/conacry/inmem-cache/internal/slru"
+	tinylfucache "github.com/conacry/inmem-cache/internal/tinylfu"
 	ttlcache "github.com/conacry/inmem-cache/internal/ttl"
+	twoqcache "github.com/conacry/inmem-cache/internal/twoq"
 )
 
 type Cache[K comparable, V any] interface {
 	Get(key K) (V, bool)
 	Set(key K, value V) error
+	// SetWithTTL stores the value with its own time to live. NoExpiration
+	// makes the entry live until it is evicted or deleted.
+	SetWithTTL(key K, value V, ttl time.Duration) error
+	// SetWithDeadline stores the value until the deadline. The zero time
+	// makes the entry live until it is evicted or deleted.
+	SetWithDeadline(key K, value V, deadline time.Time) error
+	Delete(key K) bool
+	// Len returns the number of stored entries. Expired entries that have
+	// not been accessed yet are counted as well.
+	Len() int
+	Clear()
+	Stats() Stats
+	ResetStats()
+	// Close releases background resources held by the cache, such as the
+	// goroutine removing expired entries. It should be called once the
+	// cache is no longer needed. The cache stays usable after Close,
+	// expired entries are still removed on access.
+	Close()
+}
+
+// WeightedCache is a Cache whose entries may have a cost other than 1. The
+// capacity of such a cache limits the total cost of its entries. LRU and
+// LFU caches implement it.
+type WeightedCache[K comparable, V any] interface {
+	Cache[K, V]
+	// SetWithCost stores the value with the given cost and the default TTL,
+	// evicting entries until it fits. Entries stored by other setters cost
+	// 1. A *CostTooLargeError is returned if the cost exceeds the capacity
+	// of the cache, and ErrCostCapacityRequired if the cache is limited by
+	// max bytes only.
+	SetWithCost(key K, value V, cost int64) error
 }
 
 func NewCache[K comparable, V any](cacheType CacheType, opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if param.Shards < 0 {
+		return nil, ErrIllegalShards
+	}
+
+	if err := checkPolicyParam(cacheType, param); err != nil {
+		return nil, err
+	}
+
+	if param.Shards > 1 {
+		return makeShardedCache[K, V](cacheType, param, opts...)
+	}
+
 	switch cacheType {
 	case TtlCacheType:
 		return makeTtlCache[K, V](opts...)
@@ -21,6 +81,28 @@ func NewCache[K comparable, V any](cacheType CacheType, opts ...Option) (Cache[K
 		return makeLruCache[K, V](opts...)
 	case LfuCacheType:
 		return makeLfuCache[K, V](opts...)
+	case ArcCacheType:
+		return makeArcCache[K, V](opts...)
+	case TinyLfuCacheType:
+		return makeTinyLfuCache[K, V](opts...)
+	case SieveCacheType:
+		return makeSieveCache[K, V](opts...)
+	case S3FifoCacheType:
+		return makeS3FifoCache[K, V](opts...)
+	case ClockCacheType:
+		return makeClockCache[K, V](opts...)
+	case ClockProCacheType:
+		return makeClockProCache[K, V](opts...)
+	case TwoQCacheType:
+		return makeTwoQCache[K, V](opts...)
+	case SlruCacheType:
+		return makeSlruCache[K, V](opts...)
+	case LruKCacheType:
+		return makeLruKCache[K, V](opts...)
+	case LirsCacheType:
+		return makeLirsCache[K, V](opts...)
+	case GdsfCacheType:
+		return makeGdsfCache[K, V](opts...)
 	default:
 		return nil, fmt.Errorf("unknown cache type: %s", cacheType)
 	}
@@ -32,9 +114,33 @@ func makeTtlCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
 		param = opt(param)
 	}
 
-	ttlCacheInitParams := ttlcache.CacheInitParam{
-		Capacity: param.Capacity,
-		TTL:      param.TTL,
+	if param.BufferedReads {
+		return nil, ErrBufferedReadsUnsupported
+	}
+
+	rejectOnOverflow, err := isRejectOnOverflow(param.OverflowStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	sizer, err := getSizer[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	ttlCacheInitParams := ttlcache.CacheInitParam[K, V]{
+		Capacity:         param.Capacity,
+		MaxBytes:         param.MaxBytes,
+		Sizer:            sizer,
+		TTL:              param.TTL,
+		RejectOnOverflow: rejectOnOverflow,
+		CleanupInterval:  param.CleanupInterval,
+		OnEvict:          onEvict,
 	}
 
 	cache, err := ttlcache.NewCache[K, V](ttlCacheInitParams)
@@ -51,9 +157,24 @@ func makeLruCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
 		param = opt(param)
 	}
 
-	lruCacheInitParams := lrucache.InitParam{
-		Capacity: param.Capacity,
-		TTL:      param.TTL,
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	sizer, err := getSizer[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	lruCacheInitParams := lrucache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		MaxBytes:        param.MaxBytes,
+		Sizer:           sizer,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+		BufferedReads:   param.BufferedReads,
 	}
 
 	cache, err := lrucache.NewCache[K, V](lruCacheInitParams)
@@ -70,8 +191,26 @@ func makeLfuCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
 		param = opt(param)
 	}
 
-	lfuCacheInitParams := lfucache.InitParam{
-		Capacity: param.Capacity,
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	sizer, err := getSizer[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	lfuCacheInitParams := lfucache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		MaxBytes:        param.MaxBytes,
+		Sizer:           sizer,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+		BufferedReads:   param.BufferedReads,
+		DecayInterval:   param.LfuDecayInterval,
+		DecayFactor:     param.LfuDecayFactor,
 	}
 
 	cache, err := lfucache.NewCache[K, V](lfuCacheInitParams)
@@ -81,3 +220,478 @@ func makeLfuCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
 
 	return cache, nil
 }
+
+func makeArcCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if err := checkCountBasedParam(param); err != nil {
+		return nil, err
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	arcCacheInitParams := arccache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := arccache.NewCache[K, V](arcCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ARC cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func makeTinyLfuCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if err := checkCountBasedParam(param); err != nil {
+		return nil, err
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher, err := getHasher[K](param)
+	if err != nil {
+		return nil, err
+	}
+
+	tinyLfuCacheInitParams := tinylfucache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		Hasher:          hasher,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := tinylfucache.NewCache[K, V](tinyLfuCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TinyLFU cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func makeSieveCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if err := checkCountBasedParam(param); err != nil {
+		return nil, err
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	sieveCacheInitParams := sievecache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := sievecache.NewCache[K, V](sieveCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SIEVE cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func makeS3FifoCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if param.BufferedReads {
+		return nil, ErrBufferedReadsUnsupported
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	sizer, err := getSizer[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	s3FifoCacheInitParams := s3fifocache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		MaxBytes:        param.MaxBytes,
+		Sizer:           sizer,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := s3fifocache.NewCache[K, V](s3FifoCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3-FIFO cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func makeClockCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if err := checkCountBasedParam(param); err != nil {
+		return nil, err
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	clockCacheInitParams := clockcache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := clockcache.NewCache[K, V](clockCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CLOCK cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func makeClockProCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if err := checkCountBasedParam(param); err != nil {
+		return nil, err
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	clockproCacheInitParams := clockprocache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := clockprocache.NewCache[K, V](clockproCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CLOCK-Pro cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func makeTwoQCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if err := checkCountBasedParam(param); err != nil {
+		return nil, err
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	twoQCacheInitParams := twoqcache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := twoqcache.NewCache[K, V](twoQCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create 2Q cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func makeSlruCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if err := checkCountBasedParam(param); err != nil {
+		return nil, err
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	slruCacheInitParams := slrucache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		ProtectedRatio:  param.SlruProtectedRatio,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := slrucache.NewCache[K, V](slruCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SLRU cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func makeLruKCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if err := checkCountBasedParam(param); err != nil {
+		return nil, err
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	lruKCacheInitParams := lrukcache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		K:               param.K,
+		HistorySize:     param.HistorySize,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := lrukcache.NewCache[K, V](lruKCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LRU-K cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func makeLirsCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if err := checkCountBasedParam(param); err != nil {
+		return nil, err
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	lirsCacheInitParams := lirscache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := lirscache.NewCache[K, V](lirsCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LIRS cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func makeGdsfCache[K comparable, V any](opts ...Option) (Cache[K, V], error) {
+	param := CacheInitParam{}
+	for _, opt := range opts {
+		param = opt(param)
+	}
+
+	if param.BufferedReads {
+		return nil, ErrBufferedReadsUnsupported
+	}
+
+	onEvict, err := getOnEvict[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	sizer, err := getSizer[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	cost, err := getGdsfCost[K, V](param)
+	if err != nil {
+		return nil, err
+	}
+
+	gdsfCacheInitParams := gdsfcache.InitParam[K, V]{
+		Capacity:        param.Capacity,
+		MaxBytes:        param.MaxBytes,
+		Sizer:           sizer,
+		Cost:            cost,
+		TTL:             param.TTL,
+		CleanupInterval: param.CleanupInterval,
+		OnEvict:         onEvict,
+	}
+
+	cache, err := gdsfcache.NewCache[K, V](gdsfCacheInitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GDSF cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+// checkCountBasedParam rejects options which are not supported by caches
+// limited by the number of entries only.
+func checkCountBasedParam(param CacheInitParam) error {
+	if param.MaxBytes != 0 {
+		return ErrMaxBytesUnsupported
+	}
+
+	if param.BufferedReads {
+		return ErrBufferedReadsUnsupported
+	}
+
+	return nil
+}
+
+// checkPolicyParam rejects options which tune the eviction policy of another
+// cache type.
+func checkPolicyParam(cacheType CacheType, param CacheInitParam) error {
+	rejectOnOverflow, err := isRejectOnOverflow(param.OverflowStrategy)
+	if err != nil {
+		return err
+	}
+
+	if rejectOnOverflow && cacheType != TtlCacheType {
+		return ErrRejectOverflowUnsupported
+	}
+
+	if param.SlruProtectedRatio != 0 && cacheType != SlruCacheType {
+		return ErrSlruProtectedRatioUnsupported
+	}
+
+	if param.K != 0 && cacheType != LruKCacheType {
+		return ErrKUnsupported
+	}
+
+	if param.HistorySize != 0 && cacheType != LruKCacheType {
+		return ErrHistorySizeUnsupported
+	}
+
+	if (param.LfuDecayInterval != 0 || param.LfuDecayFactor != 0) && cacheType != LfuCacheType {
+		return ErrLfuDecayUnsupported
+	}
+
+	if param.GdsfCost != nil && cacheType != GdsfCacheType {
+		return ErrGdsfCostUnsupported
+	}
+
+	return nil
+}
+
+func isRejectOnOverflow(strategy OverflowStrategy) (bool, error) {
+	switch strategy {
+	case "", EvictOverflowStrategy:
+		return false, nil
+	case RejectOverflowStrategy:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown overflow strategy: %s", strategy)
+	}
+}
+
+func getOnEvict[K comparable, V any](param CacheInitParam) (func(K, V, RemovalReason), error) {
+	if param.OnEvict == nil {
+		return nil, nil
+	}
+
+	onEvict, ok := param.OnEvict.(func(K, V, RemovalReason))
+	if !ok {
+		return nil, ErrIllegalOnEvict
+	}
+
+	return onEvict, nil
+}
+
+func getGdsfCost[K comparable, V any](param CacheInitParam) (func(K, V) int64, error) {
+	if param.GdsfCost == nil {
+		return nil, nil
+	}
+
+	cost, ok := param.GdsfCost.(func(K, V) int64)
+	if !ok {
+		return nil, ErrIllegalGdsfCost
+	}
+
+	return cost, nil
+}
+
+func getSizer[K comparable, V any](param CacheInitParam) (func(K, V) int64, error) {
+	if param.MaxBytes <= 0 {
+		return nil, nil
+	}
+
+	if param.Sizer == nil {
+		return defaultSizer[K, V]()
+	}
+
+	sizer, ok := param.Sizer.(Sizer[K, V])
+	if !ok {
+		return nil, ErrIllegalSizer
+	}
+
+	return sizer.Size, nil
+}
+
+func getHasher[K comparable](param CacheInitParam) (func(K) uint64, error) {
+	if param.Hasher == nil {
+		return defaultHasher[K]()
+	}
+
+	hasher, ok := param.Hasher.(Hasher[K])
+	if !ok {
+		return nil, ErrIllegalHasher
+	}
+
+	return hasher.Hash, nil
+}
@@ -6,9 +6,20 @@ import (
 	"testing"
 	"time"
 
+	arccache "github.com/conacry/inmem-cache/internal/arc"
+	clockcache "github.com/conacry/inmem-cache/internal/clock"
+	clockprocache "github.com/conacry/inmem-cache/internal/clockpro"
+	gdsfcache "github.com/conacry/inmem-cache/internal/gdsf"
 	lfucache "github.com/conacry/inmem-cache/internal/lfu"
+	lirscache "github.com/conacry/inmem-cache/internal/lirs"
 	lrucache "github.com/conacry/inmem-cache/internal/lru"
+	lrukcache "github.com/conacry/inmem-cache/internal/lruk"
+	s3fifocache "github.com/conacry/inmem-cache/internal/s3fifo"
+	sievecache "github.com/conacry/inmem-cache/internal/sieve"
+	slrucache "github.com/conacry/inmem-cache/internal/slru"
+	tinylfucache "github.com/conacry/inmem-cache/internal/tinylfu"
 	ttlcache "github.com/conacry/inmem-cache/internal/ttl"
+	twoqcache "github.com/conacry/inmem-cache/internal/twoq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -58,7 +69,7 @@ func (s *CacheSuite) TestNewCache_TtlCacheType_ReturnCache() {
 
 func (s *CacheSuite) TestNewCache_LruCacheTypeWithoutCapacity_ReturnError() {
 	lruCacheType := CacheType("lru")
-	cacheErr := fmt.Errorf("capacity should be greater than 0")
+	cacheErr := fmt.Errorf("capacity should be greater than 0 unless max bytes is set")
 	expectedErr := fmt.Errorf("failed to create LRU cache: %w", cacheErr)
 
 	cache, err := NewCache[string, string](lruCacheType)
@@ -96,7 +107,7 @@ func (s *CacheSuite) TestNewCache_LruCacheType_ReturnCache() {
 
 func (s *CacheSuite) TestNewCache_LfuCacheTypeWithoutCapacity_ReturnError() {
 	lfuCacheType := LfuCacheType
-	cacheErr := fmt.Errorf("capacity should be greater than 0")
+	cacheErr := fmt.Errorf("capacity should be greater than 0 unless max bytes is set")
 	expectedErr := fmt.Errorf("failed to create LFU cache: %w", cacheErr)
 
 	cache, err := NewCache[string, string](lfuCacheType)
@@ -116,3 +127,574 @@ func (s *CacheSuite) TestNewCache_LfuCacheType_ReturnCache() {
 	assert.NotNil(s.T(), cache)
 	assert.IsType(s.T(), &lfucache.Cache[string, string]{}, cache)
 }
+
+func (s *CacheSuite) TestNewCache_ArcCacheTypeWithoutCapacity_ReturnError() {
+	cacheErr := fmt.Errorf("capacity should be greater than 0")
+	expectedErr := fmt.Errorf("failed to create ARC cache: %w", cacheErr)
+
+	cache, err := NewCache[string, string](ArcCacheType)
+	assert.Nil(s.T(), cache)
+	require.Error(s.T(), err)
+	assert.Equal(s.T(), expectedErr.Error(), err.Error())
+}
+
+func (s *CacheSuite) TestNewCache_ArcCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+	}
+
+	cache, err := NewCache[string, string](ArcCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &arccache.Cache[string, string]{}, cache)
+}
+
+func (s *CacheSuite) TestNewCache_TinyLfuCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+	}
+
+	cache, err := NewCache[string, string](TinyLfuCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &tinylfucache.Cache[string, string]{}, cache)
+}
+
+func (s *CacheSuite) TestNewCache_TinyLfuCacheTypeWithHasher_HasherWasUsed() {
+	calls := 0
+	hasher := HasherFunc[string](func(key string) uint64 {
+		calls++
+		return uint64(len(key))
+	})
+
+	cache, err := NewCache[string, string](TinyLfuCacheType, WithCapacity(50), WithHasher[string](hasher))
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key", "value")
+	require.NoError(s.T(), err)
+	assert.Positive(s.T(), calls)
+
+	structCache, err := NewCache[struct{ id int }, string](TinyLfuCacheType, WithCapacity(50))
+	assert.Nil(s.T(), structCache)
+	assert.ErrorIs(s.T(), err, ErrHasherRequired)
+}
+
+func (s *CacheSuite) TestNewCache_SieveCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+	}
+
+	cache, err := NewCache[string, string](SieveCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &sievecache.Cache[string, string]{}, cache)
+}
+
+func (s *CacheSuite) TestNewCache_S3FifoCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+	}
+
+	cache, err := NewCache[string, string](S3FifoCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &s3fifocache.Cache[string, string]{}, cache)
+}
+
+func (s *CacheSuite) TestNewCache_ClockCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+	}
+
+	cache, err := NewCache[string, string](ClockCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &clockcache.Cache[string, string]{}, cache)
+}
+
+func (s *CacheSuite) TestNewCache_ClockProCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+	}
+
+	cache, err := NewCache[string, string](ClockProCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &clockprocache.Cache[string, string]{}, cache)
+}
+
+func (s *CacheSuite) TestNewCache_TwoQCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+	}
+
+	cache, err := NewCache[string, string](TwoQCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &twoqcache.Cache[string, string]{}, cache)
+}
+
+func (s *CacheSuite) TestNewCache_SlruCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+	}
+
+	cache, err := NewCache[string, string](SlruCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &slrucache.Cache[string, string]{}, cache)
+}
+
+func (s *CacheSuite) TestNewCache_SlruProtectedRatio_ReturnCache() {
+	cache, err := NewCache[string, string](SlruCacheType, WithCapacity(50), WithSlruProtectedRatio(0.5))
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+
+	for _, ratio := range []float64{-0.5, 1, 1.5} {
+		cache, err = NewCache[string, string](SlruCacheType, WithCapacity(50), WithSlruProtectedRatio(ratio))
+		assert.Nil(s.T(), cache)
+		assert.ErrorIs(s.T(), err, slrucache.ErrIllegalProtectedRatio)
+	}
+}
+
+func (s *CacheSuite) TestNewCache_LfuDecay_ReturnCache() {
+	cache, err := NewCache[string, string](LfuCacheType, WithCapacity(50), WithLfuDecay(time.Minute, 0.5))
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	cache.Close()
+
+	cache, err = NewCache[string, string](LfuCacheType, WithCapacity(50), WithLfuDecay(-time.Minute, 0.5))
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, lfucache.ErrIllegalDecayInterval)
+
+	cache, err = NewCache[string, string](LfuCacheType, WithCapacity(50), WithLfuDecay(time.Minute, 1.5))
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, lfucache.ErrIllegalDecayFactor)
+
+	cache, err = NewCache[string, string](LfuCacheType, WithCapacity(50), WithLfuDecay(time.Minute, 0))
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, lfucache.ErrIllegalDecayFactor)
+}
+
+func (s *CacheSuite) TestNewCache_LruKCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+		WithK(3),
+		WithHistorySize(100),
+	}
+
+	cache, err := NewCache[string, string](LruKCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &lrukcache.Cache[string, string]{}, cache)
+
+	cache, err = NewCache[string, string](LruKCacheType, WithCapacity(50), WithK(-1))
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, lrukcache.ErrIllegalK)
+
+	cache, err = NewCache[string, string](LruKCacheType, WithCapacity(50), WithHistorySize(-1))
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, lrukcache.ErrIllegalHistorySize)
+}
+
+func (s *CacheSuite) TestNewCache_LirsCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+	}
+
+	cache, err := NewCache[string, string](LirsCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &lirscache.Cache[string, string]{}, cache)
+}
+
+func (s *CacheSuite) TestNewCache_GdsfCacheType_ReturnCache() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(time.Minute),
+	}
+
+	cache, err := NewCache[string, string](GdsfCacheType, opts...)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	assert.IsType(s.T(), &gdsfcache.Cache[string, string]{}, cache)
+}
+
+func (s *CacheSuite) TestNewCache_GdsfCost_ReturnCache() {
+	cost := func(key string, value string) int64 { return int64(len(key)) }
+
+	cache, err := NewCache[string, string](GdsfCacheType, WithCapacity(50), WithGdsfCost(cost))
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+
+	intCache, err := NewCache[string, int](GdsfCacheType, WithCapacity(50), WithGdsfCost(cost))
+	assert.Nil(s.T(), intCache)
+	assert.ErrorIs(s.T(), err, ErrIllegalGdsfCost)
+}
+
+func (s *CacheSuite) TestNewCache_UnsupportedOptions_ReturnError() {
+	cache, err := NewCache[string, string](ArcCacheType, WithCapacity(50), WithMaxBytes(100))
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrMaxBytesUnsupported)
+
+	cache, err = NewCache[string, string](ArcCacheType, WithCapacity(50), WithBufferedReads())
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrBufferedReadsUnsupported)
+
+	cache, err = NewCache[string, string](TinyLfuCacheType, WithCapacity(50), WithMaxBytes(100))
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrMaxBytesUnsupported)
+}
+
+func (s *CacheSuite) TestNewCache_OptionsOfAnotherPolicy_ReturnError() {
+	testCases := []struct {
+		cacheType CacheType
+		opt       Option
+		err       error
+	}{
+		{LruCacheType, WithOverflowStrategy(RejectOverflowStrategy), ErrRejectOverflowUnsupported},
+		{LruCacheType, WithSlruProtectedRatio(0.5), ErrSlruProtectedRatioUnsupported},
+		{SlruCacheType, WithK(3), ErrKUnsupported},
+		{LruCacheType, WithHistorySize(10), ErrHistorySizeUnsupported},
+		{LruKCacheType, WithLfuDecay(time.Minute, 0.5), ErrLfuDecayUnsupported},
+		{LfuCacheType, WithGdsfCost(func(string, string) int64 { return 1 }), ErrGdsfCostUnsupported},
+	}
+
+	for _, tc := range testCases {
+		cache, err := NewCache[string, string](tc.cacheType, WithCapacity(50), tc.opt)
+		assert.Nil(s.T(), cache, "cache type: %s", tc.cacheType)
+		assert.ErrorIs(s.T(), err, tc.err, "cache type: %s", tc.cacheType)
+
+		cache, err = NewCache[string, string](tc.cacheType, WithCapacity(50), WithShards(2), tc.opt)
+		assert.Nil(s.T(), cache, "cache type: %s", tc.cacheType)
+		assert.ErrorIs(s.T(), err, tc.err, "cache type: %s", tc.cacheType)
+	}
+
+	cache, err := NewCache[string, string](LruCacheType, WithCapacity(50), WithOverflowStrategy("unknown"))
+	assert.Nil(s.T(), cache)
+	assert.Error(s.T(), err)
+
+	cache, err = NewCache[string, string](LruCacheType, WithCapacity(50), WithTTL(time.Minute), WithOverflowStrategy(EvictOverflowStrategy))
+	require.NoError(s.T(), err)
+	assert.NotNil(s.T(), cache)
+	cache.Close()
+}
+
+func (s *CacheSuite) TestNewCache_WithCleanupInterval_ExpiredValueWasRemoved() {
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(50 * time.Millisecond),
+		WithCleanupInterval(10 * time.Millisecond),
+	}
+
+	for _, cacheType := range []CacheType{TtlCacheType, LruCacheType, LfuCacheType, ArcCacheType, TinyLfuCacheType, SieveCacheType, S3FifoCacheType, ClockCacheType, ClockProCacheType, TwoQCacheType, SlruCacheType, LruKCacheType, LirsCacheType, GdsfCacheType} {
+		cache, err := NewCache[string, string](cacheType, opts...)
+		require.NoError(s.T(), err)
+		require.NotNil(s.T(), cache)
+
+		err = cache.Set("key", "value")
+		require.NoError(s.T(), err)
+
+		assert.Eventually(s.T(), func() bool {
+			return cache.Len() == 0
+		}, time.Second, 10*time.Millisecond, "cache type: %s", cacheType)
+
+		cache.Close()
+	}
+}
+
+func (s *CacheSuite) TestNewCache_TtlCacheTypeWithRejectOverflowStrategy_ReturnErrorWhenFull() {
+	opts := []Option{
+		WithCapacity(1),
+		WithTTL(time.Minute),
+		WithOverflowStrategy(RejectOverflowStrategy),
+	}
+
+	cache, err := NewCache[string, string](TtlCacheType, opts...)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	err = cache.Set("key1", "value1")
+	require.NoError(s.T(), err)
+
+	err = cache.Set("key2", "value2")
+	assert.ErrorIs(s.T(), err, ttlcache.ErrCacheIsFull)
+}
+
+func (s *CacheSuite) TestNewCache_UnknownOverflowStrategy_ReturnError() {
+	unknownStrategy := OverflowStrategy("unknown")
+	expectedError := fmt.Errorf("unknown overflow strategy: %s", unknownStrategy)
+	opts := []Option{
+		WithTTL(time.Minute),
+		WithOverflowStrategy(unknownStrategy),
+	}
+
+	cache, err := NewCache[string, string](TtlCacheType, opts...)
+	assert.Nil(s.T(), cache)
+	require.Error(s.T(), err)
+	assert.Equal(s.T(), expectedError.Error(), err.Error())
+}
+
+func (s *CacheSuite) TestNewCache_SetWithNoExpiration_ValueNeverExpires() {
+	ttl := 50 * time.Millisecond
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(ttl),
+	}
+
+	for _, cacheType := range []CacheType{TtlCacheType, LruCacheType, LfuCacheType, ArcCacheType, TinyLfuCacheType, SieveCacheType, S3FifoCacheType, ClockCacheType, ClockProCacheType, TwoQCacheType, SlruCacheType, LruKCacheType, LirsCacheType, GdsfCacheType} {
+		cache, err := NewCache[string, string](cacheType, opts...)
+		require.NoError(s.T(), err)
+		require.NotNil(s.T(), cache)
+
+		err = cache.SetWithTTL("eternal", "value", NoExpiration)
+		require.NoError(s.T(), err)
+		err = cache.SetWithTTL("shortLived", "value", ttl/2)
+		require.NoError(s.T(), err)
+
+		time.Sleep(ttl + 20*time.Millisecond)
+
+		_, exists := cache.Get("eternal")
+		assert.True(s.T(), exists, "cache type: %s", cacheType)
+
+		_, exists = cache.Get("shortLived")
+		assert.False(s.T(), exists, "cache type: %s", cacheType)
+	}
+}
+
+func (s *CacheSuite) TestNewCache_LfuCacheTypeWithTTL_ValueExpired() {
+	ttl := 50 * time.Millisecond
+	opts := []Option{
+		WithCapacity(50),
+		WithTTL(ttl),
+	}
+
+	cache, err := NewCache[string, string](LfuCacheType, opts...)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), cache)
+
+	err = cache.Set("key", "value")
+	require.NoError(s.T(), err)
+
+	time.Sleep(ttl + 20*time.Millisecond)
+
+	_, exists := cache.Get("key")
+	assert.False(s.T(), exists)
+}
+
+func (s *CacheSuite) TestNewCache_WithOnEvict_CallbackWasCalledOnEviction() {
+	type eviction struct {
+		key    string
+		value  string
+		reason RemovalReason
+	}
+
+	for _, cacheType := range []CacheType{TtlCacheType, LruCacheType, LfuCacheType, ArcCacheType, TinyLfuCacheType, SieveCacheType, S3FifoCacheType, ClockCacheType, ClockProCacheType, TwoQCacheType, SlruCacheType, LruKCacheType, LirsCacheType, GdsfCacheType} {
+		var evictions []eviction
+		opts := []Option{
+			WithCapacity(1),
+			WithTTL(time.Minute),
+			WithOnEvict(func(key string, value string, reason RemovalReason) {
+				evictions = append(evictions, eviction{key: key, value: value, reason: reason})
+			}),
+		}
+
+		cache, err := NewCache[string, string](cacheType, opts...)
+		require.NoError(s.T(), err)
+		require.NotNil(s.T(), cache)
+
+		err = cache.Set("key1", "value1")
+		require.NoError(s.T(), err)
+		err = cache.Set("key2", "value2")
+		require.NoError(s.T(), err)
+
+		expected := []eviction{{key: "key1", value: "value1", reason: CapacityRemovalReason}}
+		assert.Equal(s.T(), expected, evictions, "cache type: %s", cacheType)
+	}
+}
+
+func (s *CacheSuite) TestNewCache_WithOnEvictOfOtherTypes_ReturnError() {
+	opts := []Option{
+		WithCapacity(1),
+		WithTTL(time.Minute),
+		WithOnEvict(func(key int, value string, reason RemovalReason) {}),
+	}
+
+	for _, cacheType := range []CacheType{TtlCacheType, LruCacheType, LfuCacheType, ArcCacheType, TinyLfuCacheType, SieveCacheType, S3FifoCacheType, ClockCacheType, ClockProCacheType, TwoQCacheType, SlruCacheType, LruKCacheType, LirsCacheType, GdsfCacheType} {
+		cache, err := NewCache[string, string](cacheType, opts...)
+		assert.Nil(s.T(), cache)
+		assert.ErrorIs(s.T(), err, ErrIllegalOnEvict, "cache type: %s", cacheType)
+	}
+}
+
+func (s *CacheSuite) TestNewCache_Stats_CountersWereCollected() {
+	opts := []Option{
+		WithCapacity(10),
+		WithTTL(time.Minute),
+	}
+
+	for _, cacheType := range []CacheType{TtlCacheType, LruCacheType, LfuCacheType, ArcCacheType, TinyLfuCacheType, SieveCacheType, S3FifoCacheType, ClockCacheType, ClockProCacheType, TwoQCacheType, SlruCacheType, LruKCacheType, LirsCacheType, GdsfCacheType} {
+		cache, err := NewCache[string, string](cacheType, opts...)
+		require.NoError(s.T(), err)
+		require.NotNil(s.T(), cache)
+
+		err = cache.Set("key1", "value1")
+		require.NoError(s.T(), err)
+		err = cache.Set("key1", "value2")
+		require.NoError(s.T(), err)
+		_, _ = cache.Get("key1")
+		_, _ = cache.Get("key2")
+		cache.Delete("key1")
+
+		expected := Stats{
+			Hits:         1,
+			Misses:       1,
+			Sets:         2,
+			Deletions:    1,
+			Replacements: 1,
+		}
+		assert.Equal(s.T(), expected, cache.Stats(), "cache type: %s", cacheType)
+		assert.Equal(s.T(), 0.5, cache.Stats().HitRatio(), "cache type: %s", cacheType)
+	}
+}
+
+func (s *CacheSuite) TestNewCache_WeightedCache_ValuesWereEvictedByCost() {
+	opts := []Option{
+		WithCapacity(10),
+		WithTTL(time.Minute),
+	}
+
+	for _, cacheType := range []CacheType{LruCacheType, LfuCacheType} {
+		cache, err := NewCache[string, int](cacheType, opts...)
+		require.NoError(s.T(), err, "cache type: %s", cacheType)
+		require.NotNil(s.T(), cache)
+
+		weighted, ok := cache.(WeightedCache[string, int])
+		require.True(s.T(), ok, "cache type: %s", cacheType)
+
+		err = weighted.SetWithCost("key1", 1, 6)
+		require.NoError(s.T(), err)
+		err = weighted.SetWithCost("key2", 2, 6)
+		require.NoError(s.T(), err)
+
+		assert.Equal(s.T(), 1, cache.Len(), "cache type: %s", cacheType)
+		_, exists := cache.Get("key2")
+		assert.True(s.T(), exists, "cache type: %s", cacheType)
+
+		err = weighted.SetWithCost("key3", 3, 11)
+		var costErr *CostTooLargeError
+		assert.ErrorAs(s.T(), err, &costErr, "cache type: %s", cacheType)
+	}
+}
+
+func (s *CacheSuite) TestNewCache_WithMaxBytes_ValuesWereEvictedByBytes() {
+	opts := []Option{
+		WithTTL(time.Minute),
+		WithMaxBytes(10),
+	}
+
+	for _, cacheType := range []CacheType{TtlCacheType, LruCacheType, LfuCacheType, S3FifoCacheType, GdsfCacheType} {
+		cache, err := NewCache[string, []byte](cacheType, opts...)
+		require.NoError(s.T(), err, "cache type: %s", cacheType)
+		require.NotNil(s.T(), cache)
+
+		err = cache.Set("key1", make([]byte, 6))
+		require.NoError(s.T(), err)
+		err = cache.Set("key2", make([]byte, 6))
+		require.NoError(s.T(), err)
+
+		assert.Equal(s.T(), 1, cache.Len(), "cache type: %s", cacheType)
+		_, exists := cache.Get("key2")
+		assert.True(s.T(), exists, "cache type: %s", cacheType)
+	}
+}
+
+func (s *CacheSuite) TestNewCache_WithMaxBytesAndSizer_SizerWasUsed() {
+	sizer := SizerFunc[int, int](func(key int, value int) int64 {
+		return int64(value)
+	})
+	opts := []Option{
+		WithTTL(time.Minute),
+		WithMaxBytes(10),
+		WithSizer[int, int](sizer),
+	}
+
+	for _, cacheType := range []CacheType{TtlCacheType, LruCacheType, LfuCacheType, S3FifoCacheType, GdsfCacheType} {
+		cache, err := NewCache[int, int](cacheType, opts...)
+		require.NoError(s.T(), err, "cache type: %s", cacheType)
+		require.NotNil(s.T(), cache)
+
+		err = cache.Set(1, 4)
+		require.NoError(s.T(), err)
+		err = cache.Set(2, 4)
+		require.NoError(s.T(), err)
+		err = cache.Set(3, 4)
+		require.NoError(s.T(), err)
+
+		assert.Equal(s.T(), 2, cache.Len(), "cache type: %s", cacheType)
+	}
+}
+
+func (s *CacheSuite) TestNewCache_WithMaxBytesForUnsupportedValue_ReturnError() {
+	opts := []Option{
+		WithTTL(time.Minute),
+		WithMaxBytes(10),
+	}
+
+	for _, cacheType := range []CacheType{TtlCacheType, LruCacheType, LfuCacheType, S3FifoCacheType, GdsfCacheType} {
+		cache, err := NewCache[string, int](cacheType, opts...)
+		assert.Nil(s.T(), cache)
+		assert.ErrorIs(s.T(), err, ErrSizerRequired, "cache type: %s", cacheType)
+	}
+}
+
+func (s *CacheSuite) TestNewCache_WithSizerOfOtherTypes_ReturnError() {
+	sizer := SizerFunc[int, int](func(key int, value int) int64 {
+		return int64(value)
+	})
+	opts := []Option{
+		WithTTL(time.Minute),
+		WithMaxBytes(10),
+		WithSizer[int, int](sizer),
+	}
+
+	for _, cacheType := range []CacheType{TtlCacheType, LruCacheType, LfuCacheType, S3FifoCacheType, GdsfCacheType} {
+		cache, err := NewCache[string, string](cacheType, opts...)
+		assert.Nil(s.T(), cache)
+		assert.ErrorIs(s.T(), err, ErrIllegalSizer, "cache type: %s", cacheType)
+	}
+}
+
+func (s *CacheSuite) TestNewCache_WithBufferedReads_ValuesWereStored() {
+	opts := []Option{
+		WithCapacity(10),
+		WithTTL(time.Minute),
+		WithBufferedReads(),
+	}
+
+	for _, cacheType := range []CacheType{LruCacheType, LfuCacheType} {
+		cache, err := NewCache[string, int](cacheType, opts...)
+		require.NoError(s.T(), err, "cache type: %s", cacheType)
+
+		err = cache.Set("key1", 1)
+		require.NoError(s.T(), err)
+
+		value, exists := cache.Get("key1")
+		assert.True(s.T(), exists, "cache type: %s", cacheType)
+		assert.Equal(s.T(), 1, value, "cache type: %s", cacheType)
+	}
+
+	cache, err := NewCache[string, int](TtlCacheType, opts...)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrBufferedReadsUnsupported)
+}
@@ -0,0 +1,31 @@
+package inmem
+
+import (
+	"errors"
+
+	"github.com/conacry/inmem-cache/internal/cacheerr"
+)
+
+var (
+	ErrIllegalOnEvict                = errors.New("on evict callback should match key and value types of the cache")
+	ErrIllegalSizer                  = errors.New("sizer should match key and value types of the cache")
+	ErrSizerRequired                 = errors.New("sizer is required when max bytes is set")
+	ErrIllegalShards                 = errors.New("shards should not be negative")
+	ErrTooManyShards                 = errors.New("capacity and max bytes should not be less than the number of shards")
+	ErrIllegalHasher                 = errors.New("hasher should match key type of the cache")
+	ErrHasherRequired                = errors.New("hasher is required for the key type")
+	ErrBufferedReadsUnsupported      = errors.New("buffered reads are supported by LRU and LFU caches only")
+	ErrCostCapacityRequired          = cacheerr.ErrCostCapacityRequired
+	ErrMaxBytesUnsupported           = errors.New("max bytes is not supported by the cache type")
+	ErrRejectOverflowUnsupported     = errors.New("reject overflow strategy is supported by TTL caches only")
+	ErrSlruProtectedRatioUnsupported = errors.New("protected ratio is supported by SLRU caches only")
+	ErrKUnsupported                  = errors.New("k is supported by LRU-K caches only")
+	ErrHistorySizeUnsupported        = errors.New("history size is supported by LRU-K caches only")
+	ErrGdsfCostUnsupported           = errors.New("cost of a miss is supported by GDSF caches only")
+	ErrIllegalGdsfCost               = errors.New("gdsf cost should match key and value types of the cache")
+	ErrLfuDecayUnsupported           = errors.New("use count decay is supported by LFU caches only")
+)
+
+// CostTooLargeError is returned by WeightedCache.SetWithCost when the cost of
+// the entry exceeds the capacity of the cache.
+type CostTooLargeError = cacheerr.CostTooLargeError
@@ -0,0 +1,65 @@
+package inmem
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// Hasher distributes keys between the shards of a cache created with
+// WithShards. Equal keys must have equal hashes.
+type Hasher[K comparable] interface {
+	Hash(key K) uint64
+}
+
+type HasherFunc[K comparable] func(key K) uint64
+
+func (f HasherFunc[K]) Hash(key K) uint64 {
+	return f(key)
+}
+
+// defaultHasher returns a hasher for keys which are strings, integers or
+// floats, including named types based on them.
+func defaultHasher[K comparable]() (func(key K) uint64, error) {
+	keyType := reflect.TypeFor[K]()
+	seed := maphash.MakeSeed()
+
+	switch keyType.Kind() {
+	case reflect.String:
+		return func(key K) uint64 {
+			return maphash.String(seed, reflect.ValueOf(key).String())
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(key K) uint64 {
+			return mix(uint64(reflect.ValueOf(key).Int()))
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(key K) uint64 {
+			return mix(reflect.ValueOf(key).Uint())
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(key K) uint64 {
+			f := reflect.ValueOf(key).Float()
+			if f == 0 {
+				// -0 and +0 are equal keys with different bits.
+				f = 0
+			}
+
+			return mix(math.Float64bits(f))
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: no default hasher for %s", ErrHasherRequired, keyType)
+	}
+}
+
+// mix spreads the bits of x, so that sequential integer keys do not end up
+// in neighbouring shards only.
+func mix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,56 @@
+package inmem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namedInt int
+
+func TestDefaultHasher(t *testing.T) {
+	t.Run("String key", func(t *testing.T) {
+		hasher, err := defaultHasher[string]()
+		require.NoError(t, err)
+		assert.Equal(t, hasher("key"), hasher("key"))
+		assert.NotEqual(t, hasher("key1"), hasher("key2"))
+	})
+
+	t.Run("Named integer key", func(t *testing.T) {
+		hasher, err := defaultHasher[namedInt]()
+		require.NoError(t, err)
+		assert.Equal(t, hasher(1), hasher(1))
+		assert.NotEqual(t, hasher(1), hasher(2))
+	})
+
+	t.Run("Unsigned key", func(t *testing.T) {
+		hasher, err := defaultHasher[uint32]()
+		require.NoError(t, err)
+		assert.NotEqual(t, hasher(1), hasher(2))
+	})
+
+	t.Run("Float zero keys", func(t *testing.T) {
+		hasher, err := defaultHasher[float64]()
+		require.NoError(t, err)
+		negativeZero := 0.0
+		negativeZero = -negativeZero
+		assert.Equal(t, hasher(0), hasher(negativeZero))
+	})
+
+	t.Run("Unsupported key", func(t *testing.T) {
+		hasher, err := defaultHasher[[2]int]()
+		assert.Nil(t, hasher)
+		assert.ErrorIs(t, err, ErrHasherRequired)
+	})
+}
+
+func TestHasherFunc(t *testing.T) {
+	t.Run("Function is used as hasher", func(t *testing.T) {
+		var hasher Hasher[int] = HasherFunc[int](func(key int) uint64 {
+			return uint64(key * 2)
+		})
+
+		assert.Equal(t, uint64(4), hasher.Hash(2))
+	})
+}
@@ -0,0 +1,174 @@
+package inmem
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type LoaderFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// LoadingCache is a read-through wrapper around Cache. Concurrent misses for
+// the same key share a single loader call. Writes and deletes should go
+// through the wrapper, so that a load in flight does not overwrite them.
+type LoadingCache[K comparable, V any] struct {
+	Cache[K, V]
+	calls map[K]*loadCall[V]
+	mu    sync.Mutex
+}
+
+type loadCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	// panicValue is the value the loader panicked with. It is raised again
+	// in the caller which started the load.
+	panicValue any
+	// invalidated is set when the key is written or deleted while the load
+	// is in flight, the loaded value is stale then and is not stored.
+	invalidated bool
+}
+
+func NewLoadingCache[K comparable, V any](cache Cache[K, V]) *LoadingCache[K, V] {
+	return &LoadingCache[K, V]{
+		Cache: cache,
+		calls: make(map[K]*loadCall[V]),
+	}
+}
+
+// GetOrLoad returns the cached value by the key or loads it with the loader
+// and stores it in the cache. Only one loader call runs per key at a time,
+// other callers wait for its result. A loader error is returned to every
+// waiting caller and is not cached. The loaded value is returned even if
+// the cache refuses to store it.
+//
+// If the key is set, deleted or cleared through the LoadingCache while the
+// load is in flight, the loaded value is still returned to the callers but
+// is not stored, so it does not bring back a deleted entry or overwrite a
+// newer one. Writes made to the wrapped cache directly are not tracked.
+//
+// The loader runs with ctx detached from its cancellation, so a caller
+// which stops waiting because its own ctx is done does not fail the load
+// for the others.
+func (c *LoadingCache[K, V]) GetOrLoad(ctx context.Context, key K, loader LoaderFunc[K, V]) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	c.mu.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		return c.wait(ctx, call)
+	}
+
+	// A load of the key may have finished after the first lookup.
+	if value, ok := c.Cache.Get(key); ok {
+		c.mu.Unlock()
+		return value, nil
+	}
+
+	call := &loadCall[V]{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	go c.load(context.WithoutCancel(ctx), key, loader, call)
+
+	value, err := c.wait(ctx, call)
+	// The call is finished unless ctx is done, so its panic value is safe
+	// to read.
+	if ctx.Err() == nil && call.panicValue != nil {
+		panic(call.panicValue)
+	}
+
+	return value, err
+}
+
+// wait returns the result of the call, or the error of ctx if it is done
+// first.
+func (c *LoadingCache[K, V]) wait(ctx context.Context, call *loadCall[V]) (V, error) {
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zeroValue V
+		return zeroValue, ctx.Err()
+	}
+}
+
+func (c *LoadingCache[K, V]) load(ctx context.Context, key K, loader LoaderFunc[K, V], call *loadCall[V]) {
+	defer c.finish(key, call)
+	defer func() {
+		if r := recover(); r != nil {
+			call.err = fmt.Errorf("loader panicked: %v", r)
+			call.panicValue = r
+		}
+	}()
+
+	call.value, call.err = loader(ctx, key)
+	if call.err == nil {
+		c.store(key, call)
+	}
+}
+
+// store puts the loaded value into the cache unless the key was written or
+// deleted during the load. The lock is held while storing, so a write which
+// invalidates the call either comes after the value is stored or keeps it
+// from being stored.
+func (c *LoadingCache[K, V]) store(key K, call *loadCall[V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !call.invalidated {
+		_ = c.Cache.Set(key, call.value)
+	}
+}
+
+func (c *LoadingCache[K, V]) finish(key K, call *loadCall[V]) {
+	c.mu.Lock()
+	delete(c.calls, key)
+	c.mu.Unlock()
+
+	close(call.done)
+}
+
+func (c *LoadingCache[K, V]) Set(key K, value V) error {
+	c.invalidate(key)
+	return c.Cache.Set(key, value)
+}
+
+func (c *LoadingCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	c.invalidate(key)
+	return c.Cache.SetWithTTL(key, value, ttl)
+}
+
+func (c *LoadingCache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	c.invalidate(key)
+	return c.Cache.SetWithDeadline(key, value, deadline)
+}
+
+func (c *LoadingCache[K, V]) Delete(key K) bool {
+	c.invalidate(key)
+	return c.Cache.Delete(key)
+}
+
+func (c *LoadingCache[K, V]) Clear() {
+	c.mu.Lock()
+	for _, call := range c.calls {
+		call.invalidated = true
+	}
+	c.mu.Unlock()
+
+	c.Cache.Clear()
+}
+
+// invalidate keeps the load of the key in flight, if any, from storing its
+// value.
+func (c *LoadingCache[K, V]) invalidate(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if call, ok := c.calls[key]; ok {
+		call.invalidated = true
+	}
+}
@@ -0,0 +1,306 @@
+package inmem
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoadingCacheSuite struct {
+	suite.Suite
+}
+
+func TestLoadingCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(LoadingCacheSuite))
+}
+
+func (s *LoadingCacheSuite) TestGetOrLoad_ValueIsCached_LoaderWasNotCalled() {
+	cache := s.newLoadingCache()
+
+	err := cache.Set("key", "cached")
+	require.NoError(s.T(), err)
+
+	value, err := cache.GetOrLoad(context.Background(), "key", func(ctx context.Context, key string) (string, error) {
+		s.T().Fatal("loader should not be called")
+		return "", nil
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "cached", value)
+}
+
+func (s *LoadingCacheSuite) TestGetOrLoad_ValueIsNotCached_ValueWasLoadedAndStored() {
+	cache := s.newLoadingCache()
+
+	value, err := cache.GetOrLoad(context.Background(), "key", func(ctx context.Context, key string) (string, error) {
+		return "loaded_" + key, nil
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "loaded_key", value)
+
+	storedValue, exists := cache.Get("key")
+	assert.True(s.T(), exists)
+	assert.Equal(s.T(), "loaded_key", storedValue)
+}
+
+func (s *LoadingCacheSuite) TestGetOrLoad_ConcurrentMisses_LoaderWasCalledOnce() {
+	cache := s.newLoadingCache()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (string, error) {
+		calls.Add(1)
+		<-release
+		return "loaded", nil
+	}
+
+	const callers = 50
+	var wg sync.WaitGroup
+	values := make([]string, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i], errs[i] = cache.GetOrLoad(context.Background(), "key", loader)
+		}(i)
+	}
+
+	assert.Eventually(s.T(), func() bool {
+		return calls.Load() == 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(s.T(), int32(1), calls.Load())
+	for i := 0; i < callers; i++ {
+		assert.NoError(s.T(), errs[i])
+		assert.Equal(s.T(), "loaded", values[i])
+	}
+}
+
+func (s *LoadingCacheSuite) TestGetOrLoad_LoaderFailed_ErrorWasReturnedToAllWaitersAndNotCached() {
+	cache := s.newLoadingCache()
+
+	loaderErr := errors.New("loader error")
+	release := make(chan struct{})
+	var calls atomic.Int32
+	loader := func(ctx context.Context, key string) (string, error) {
+		calls.Add(1)
+		<-release
+		return "", loaderErr
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = cache.GetOrLoad(context.Background(), "key", loader)
+		}(i)
+	}
+
+	assert.Eventually(s.T(), func() bool {
+		return calls.Load() == 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < callers; i++ {
+		assert.ErrorIs(s.T(), errs[i], loaderErr)
+	}
+
+	_, exists := cache.Get("key")
+	assert.False(s.T(), exists)
+
+	value, err := cache.GetOrLoad(context.Background(), "key", func(ctx context.Context, key string) (string, error) {
+		return "loaded", nil
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "loaded", value)
+}
+
+func (s *LoadingCacheSuite) TestGetOrLoad_WaiterContextCanceled_ReturnContextError() {
+	cache := s.newLoadingCache()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (string, error) {
+		close(started)
+		<-release
+		return "loaded", nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = cache.GetOrLoad(context.Background(), "key", loader)
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := cache.GetOrLoad(ctx, "key", loader)
+	assert.ErrorIs(s.T(), err, context.Canceled)
+
+	close(release)
+	<-done
+}
+
+func (s *LoadingCacheSuite) TestGetOrLoad_FirstCallerContextCanceled_WaiterReceivedValue() {
+	cache := s.newLoadingCache()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (string, error) {
+		close(started)
+		<-release
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		return "loaded", nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := cache.GetOrLoad(ctx, "key", loader)
+		firstErr <- err
+	}()
+	<-started
+
+	type result struct {
+		value string
+		err   error
+	}
+	waiter := make(chan result)
+	go func() {
+		value, err := cache.GetOrLoad(context.Background(), "key", loader)
+		waiter <- result{value: value, err: err}
+	}()
+
+	cancel()
+	assert.ErrorIs(s.T(), <-firstErr, context.Canceled, "the first caller should stop waiting")
+
+	close(release)
+	res := <-waiter
+	require.NoError(s.T(), res.err)
+	assert.Equal(s.T(), "loaded", res.value)
+
+	value, ok := cache.Get("key")
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "loaded", value)
+}
+
+func (s *LoadingCacheSuite) TestGetOrLoad_LoaderPanicked_WaitersReceivedError() {
+	cache := s.newLoadingCache()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (string, error) {
+		close(started)
+		<-release
+		panic("boom")
+	}
+
+	go func() {
+		defer func() {
+			_ = recover()
+		}()
+		_, _ = cache.GetOrLoad(context.Background(), "key", loader)
+	}()
+	<-started
+
+	errCh := make(chan error)
+	go func() {
+		_, err := cache.GetOrLoad(context.Background(), "key", loader)
+		errCh <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	err := <-errCh
+	require.Error(s.T(), err)
+	assert.Equal(s.T(), "loader panicked: boom", err.Error())
+}
+
+func (s *LoadingCacheSuite) TestGetOrLoad_KeyDeletedDuringLoad_ValueWasNotStored() {
+	cache := s.newLoadingCache()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (string, error) {
+		close(started)
+		<-release
+		return "loaded", nil
+	}
+
+	type result struct {
+		value string
+		err   error
+	}
+	caller := make(chan result)
+	go func() {
+		value, err := cache.GetOrLoad(context.Background(), "key", loader)
+		caller <- result{value: value, err: err}
+	}()
+	<-started
+
+	cache.Delete("key")
+	close(release)
+
+	res := <-caller
+	require.NoError(s.T(), res.err)
+	assert.Equal(s.T(), "loaded", res.value)
+
+	_, ok := cache.Get("key")
+	assert.False(s.T(), ok, "a deleted key should not be brought back by the load")
+}
+
+func (s *LoadingCacheSuite) TestGetOrLoad_KeySetDuringLoad_NewerValueWasKept() {
+	cache := s.newLoadingCache()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (string, error) {
+		close(started)
+		<-release
+		return "loaded", nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = cache.GetOrLoad(context.Background(), "key", loader)
+	}()
+	<-started
+
+	err := cache.Set("key", "newer")
+	require.NoError(s.T(), err)
+	close(release)
+	<-done
+
+	value, ok := cache.Get("key")
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "newer", value)
+}
+
+func (s *LoadingCacheSuite) newLoadingCache() *LoadingCache[string, string] {
+	cache, err := NewCache[string, string](LruCacheType, WithCapacity(10), WithTTL(time.Minute))
+	require.NoError(s.T(), err)
+
+	return NewLoadingCache(cache)
+}
@@ -4,13 +4,40 @@ import (
 	"time"
 )
 
+// NoExpiration passed to Cache.SetWithTTL stores an entry which never expires.
+const NoExpiration time.Duration = 0
+
 type CacheInitParam struct {
-	Capacity int
-	TTL      time.Duration
+	Capacity         int
+	TTL              time.Duration
+	CleanupInterval  time.Duration
+	OverflowStrategy OverflowStrategy
+	OnEvict          any
+	MaxBytes         int64
+	Sizer            any
+	Shards           int
+	Hasher           any
+	BufferedReads    bool
+	// SlruProtectedRatio is the part of the capacity taken by the protected
+	// segment of an SLRU cache.
+	SlruProtectedRatio float64
+	// K is the number of remembered accesses of an LRU-K cache.
+	K int
+	// HistorySize limits the number of evicted keys whose accesses are
+	// remembered by an LRU-K cache.
+	HistorySize int
+	// LfuDecayInterval and LfuDecayFactor set how use counts of an LFU
+	// cache decay.
+	LfuDecayInterval time.Duration
+	LfuDecayFactor   float64
+	// GdsfCost returns the price of a miss of an entry of a GDSF cache.
+	GdsfCost any
 }
 
 type Option func(param CacheInitParam) CacheInitParam
 
+// WithCapacity limits the number of entries. For a WeightedCache it limits
+// the total cost of entries instead.
 func WithCapacity(capacity int) Option {
 	return func(param CacheInitParam) CacheInitParam {
 		param.Capacity = capacity
@@ -24,3 +51,147 @@ func WithTTL(ttl time.Duration) Option {
 		return param
 	}
 }
+
+// WithCleanupInterval enables a background goroutine that removes expired
+// entries every interval. The goroutine is stopped by Cache.Close.
+func WithCleanupInterval(interval time.Duration) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.CleanupInterval = interval
+		return param
+	}
+}
+
+// WithOverflowStrategy sets how a full TTL cache handles a new entry. By
+// default the entry which expires first is evicted. Other cache types always
+// evict and return ErrRejectOverflowUnsupported for RejectOverflowStrategy.
+func WithOverflowStrategy(strategy OverflowStrategy) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.OverflowStrategy = strategy
+		return param
+	}
+}
+
+// WithOnEvict sets a callback which is called for every entry leaving the
+// cache: evicted because of capacity, expired, deleted or replaced by Set.
+// The callback runs synchronously while the cache lock is held, so it must
+// be fast and must not call the cache. Key and value types of the callback
+// should match the types of the cache.
+func WithOnEvict[K comparable, V any](onEvict func(key K, value V, reason RemovalReason)) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.OnEvict = onEvict
+		return param
+	}
+}
+
+// WithMaxBytes limits the total size of cache entries in bytes. Entries are
+// measured by the sizer set with WithSizer, strings, byte slices and values
+// implementing Size() int are measured by default. With max bytes set the
+// capacity becomes optional. It is supported by TTL, LRU, LFU, S3-FIFO and
+// GDSF caches, other cache types return ErrMaxBytesUnsupported.
+func WithMaxBytes(maxBytes int64) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.MaxBytes = maxBytes
+		return param
+	}
+}
+
+// WithSizer sets how entry sizes are measured for WithMaxBytes. Key and
+// value types of the sizer should match the types of the cache.
+func WithSizer[K comparable, V any](sizer Sizer[K, V]) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.Sizer = sizer
+		return param
+	}
+}
+
+// WithShards splits the cache into n independently locked shards, so that
+// operations on keys of different shards do not contend for one lock. Keys
+// are distributed by the hasher set with WithHasher, strings and numbers are
+// hashed by default. Capacity and max bytes are split evenly across the
+// shards, and each shard evicts on its own. The OnEvict callback may be
+// called concurrently from different shards.
+func WithShards(n int) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.Shards = n
+		return param
+	}
+}
+
+// WithHasher sets how keys are hashed to distribute them between shards for
+// WithShards and to estimate their frequency in TinyLFU caches. The key type
+// of the hasher should match the key type of the cache.
+func WithHasher[K comparable](hasher Hasher[K]) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.Hasher = hasher
+		return param
+	}
+}
+
+// WithBufferedReads makes LRU and LFU caches serve hits under a shared read
+// lock. Accesses are recorded into striped ring buffers and applied to the
+// eviction order in batches, so concurrent hits do not contend. The buffers
+// are lossy: under heavy load some accesses are dropped and the eviction
+// order becomes slightly less accurate, which never affects stored values.
+// TTL caches serve hits under a read lock already and do not support it.
+func WithBufferedReads() Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.BufferedReads = true
+		return param
+	}
+}
+
+// WithSlruProtectedRatio sets the part of the capacity of an SLRU cache
+// taken by the protected segment, the rest is taken by the probationary
+// one. The ratio should be greater than 0 and less than 1, it is 0.8 by
+// default, and 0 is the same as not setting it. Other cache types return
+// ErrSlruProtectedRatioUnsupported.
+func WithSlruProtectedRatio(ratio float64) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.SlruProtectedRatio = ratio
+		return param
+	}
+}
+
+// WithK sets the number of last accesses an LRU-K cache remembers for every
+// key, it is 2 by default. Other cache types return ErrKUnsupported.
+func WithK(k int) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.K = k
+		return param
+	}
+}
+
+// WithHistorySize limits the number of evicted keys whose accesses an LRU-K
+// cache remembers, it equals the capacity by default. Other cache types
+// return ErrHistorySizeUnsupported.
+func WithHistorySize(size int) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.HistorySize = size
+		return param
+	}
+}
+
+// WithLfuDecay makes an LFU cache multiply use counts of all entries by the
+// factor every interval, so entries which were hot long ago can be evicted
+// by the current ones. The factor should be greater than 0 and less than 1,
+// 0.5 halves the counts. Other cache types return ErrLfuDecayUnsupported.
+func WithLfuDecay(interval time.Duration, factor float64) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.LfuDecayInterval = interval
+		param.LfuDecayFactor = factor
+		return param
+	}
+}
+
+// WithGdsfCost sets the price of a miss of an entry of a GDSF cache, such as
+// the time it takes to fetch the entry again. Entries which are expensive to
+// fetch are kept longer, the cost should be greater than 0 and is 1 by
+// default. Unlike the cost of WeightedCache.SetWithCost it does not limit
+// the capacity. Key and value types of the cost function should match the
+// types of the cache. Other cache types return ErrGdsfCostUnsupported.
+func WithGdsfCost[K comparable, V any](cost func(key K, value V) int64) Option {
+	return func(param CacheInitParam) CacheInitParam {
+		param.GdsfCost = cost
+		return param
+	}
+}
@@ -0,0 +1,135 @@
+package inmem
+
+import (
+	"time"
+)
+
+type shardedCache[K comparable, V any] struct {
+	shards []Cache[K, V]
+	hasher func(key K) uint64
+}
+
+// weightedShardedCache is a sharded cache over shards implementing
+// WeightedCache.
+type weightedShardedCache[K comparable, V any] struct {
+	*shardedCache[K, V]
+}
+
+func makeShardedCache[K comparable, V any](cacheType CacheType, param CacheInitParam, opts ...Option) (Cache[K, V], error) {
+	if param.Capacity > 0 && param.Capacity < param.Shards {
+		return nil, ErrTooManyShards
+	}
+
+	if param.MaxBytes > 0 && param.MaxBytes < int64(param.Shards) {
+		return nil, ErrTooManyShards
+	}
+
+	hasher, err := getHasher[K](param)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &shardedCache[K, V]{
+		shards: make([]Cache[K, V], 0, param.Shards),
+		hasher: hasher,
+	}
+
+	for i := range param.Shards {
+		shardOpts := append(opts[:len(opts):len(opts)],
+			WithShards(0),
+			WithCapacity(int(splitLimit(int64(param.Capacity), param.Shards, i))),
+			WithMaxBytes(splitLimit(param.MaxBytes, param.Shards, i)),
+		)
+
+		shard, err := NewCache[K, V](cacheType, shardOpts...)
+		if err != nil {
+			cache.Close()
+			return nil, err
+		}
+
+		cache.shards = append(cache.shards, shard)
+	}
+
+	if _, ok := cache.shards[0].(WeightedCache[K, V]); ok {
+		return weightedShardedCache[K, V]{cache}, nil
+	}
+
+	return cache, nil
+}
+
+// splitLimit returns the part of limit taken by the i-th of n shards. The
+// remainder of the division goes to the first shards.
+func splitLimit(limit int64, n int, i int) int64 {
+	part := limit / int64(n)
+	if int64(i) < limit%int64(n) {
+		part++
+	}
+
+	return part
+}
+
+func (c *shardedCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+func (c *shardedCache[K, V]) Set(key K, value V) error {
+	return c.shard(key).Set(key, value)
+}
+
+func (c *shardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	return c.shard(key).SetWithTTL(key, value, ttl)
+}
+
+func (c *shardedCache[K, V]) SetWithDeadline(key K, value V, deadline time.Time) error {
+	return c.shard(key).SetWithDeadline(key, value, deadline)
+}
+
+func (c *shardedCache[K, V]) Delete(key K) bool {
+	return c.shard(key).Delete(key)
+}
+
+// Len returns the total number of entries in all shards. The shards are
+// not locked together, so the result may be stale under concurrent writes.
+func (c *shardedCache[K, V]) Len() int {
+	length := 0
+	for _, shard := range c.shards {
+		length += shard.Len()
+	}
+
+	return length
+}
+
+func (c *shardedCache[K, V]) Clear() {
+	for _, shard := range c.shards {
+		shard.Clear()
+	}
+}
+
+func (c *shardedCache[K, V]) Stats() Stats {
+	var stats Stats
+	for _, shard := range c.shards {
+		stats = stats.Add(shard.Stats())
+	}
+
+	return stats
+}
+
+func (c *shardedCache[K, V]) ResetStats() {
+	for _, shard := range c.shards {
+		shard.ResetStats()
+	}
+}
+
+func (c *shardedCache[K, V]) Close() {
+	for _, shard := range c.shards {
+		shard.Close()
+	}
+}
+
+func (c *shardedCache[K, V]) shard(key K) Cache[K, V] {
+	return c.shards[c.hasher(key)%uint64(len(c.shards))]
+}
+
+func (c weightedShardedCache[K, V]) SetWithCost(key K, value V, cost int64) error {
+	return c.shard(key).(WeightedCache[K, V]).SetWithCost(key, value, cost)
+}
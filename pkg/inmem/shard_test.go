@@ -0,0 +1,245 @@
+package inmem
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ShardedCacheSuite struct {
+	suite.Suite
+}
+
+func TestShardedCacheSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ShardedCacheSuite))
+}
+
+func (s *ShardedCacheSuite) TestNewCache_WithShards_ValuesWereStored() {
+	opts := []Option{
+		WithCapacity(100),
+		WithTTL(time.Minute),
+		WithShards(4),
+	}
+
+	for _, cacheType := range []CacheType{TtlCacheType, LruCacheType, LfuCacheType} {
+		cache, err := NewCache[string, int](cacheType, opts...)
+		require.NoError(s.T(), err, "cache type: %s", cacheType)
+		require.IsType(s.T(), &shardedCache[string, int]{}, unwrapSharded(cache))
+
+		for i := range 50 {
+			err = cache.Set(strconv.Itoa(i), i)
+			require.NoError(s.T(), err)
+		}
+
+		assert.Equal(s.T(), 50, cache.Len(), "cache type: %s", cacheType)
+		for i := range 50 {
+			value, exists := cache.Get(strconv.Itoa(i))
+			assert.True(s.T(), exists, "cache type: %s", cacheType)
+			assert.Equal(s.T(), i, value, "cache type: %s", cacheType)
+		}
+
+		assert.True(s.T(), cache.Delete("0"))
+		assert.Equal(s.T(), 49, cache.Len(), "cache type: %s", cacheType)
+
+		cache.Clear()
+		assert.Zero(s.T(), cache.Len(), "cache type: %s", cacheType)
+		cache.Close()
+	}
+}
+
+func (s *ShardedCacheSuite) TestNewCache_WithShards_CapacityWasSplit() {
+	opts := []Option{
+		WithCapacity(10),
+		WithTTL(time.Minute),
+		WithShards(3),
+	}
+
+	cache, err := NewCache[int, int](LruCacheType, opts...)
+	require.NoError(s.T(), err)
+
+	sharded := unwrapSharded(cache)
+	for i := range 1000 {
+		err = cache.Set(i, i)
+		require.NoError(s.T(), err)
+	}
+
+	assert.Equal(s.T(), 10, cache.Len())
+	assert.Equal(s.T(), 4, sharded.shards[0].Len())
+	assert.Equal(s.T(), 3, sharded.shards[1].Len())
+	assert.Equal(s.T(), 3, sharded.shards[2].Len())
+}
+
+func (s *ShardedCacheSuite) TestNewCache_WithShards_StatsWereAggregated() {
+	opts := []Option{
+		WithCapacity(100),
+		WithTTL(time.Minute),
+		WithShards(4),
+	}
+
+	cache, err := NewCache[int, int](LfuCacheType, opts...)
+	require.NoError(s.T(), err)
+
+	for i := range 10 {
+		err = cache.Set(i, i)
+		require.NoError(s.T(), err)
+		cache.Get(i)
+		cache.Get(i + 100)
+	}
+	cache.Delete(0)
+
+	expected := Stats{
+		Hits:      10,
+		Misses:    10,
+		Sets:      10,
+		Deletions: 1,
+		Size:      9,
+	}
+	assert.Equal(s.T(), expected, cache.Stats())
+
+	cache.ResetStats()
+	assert.Equal(s.T(), Stats{Size: 9}, cache.Stats())
+}
+
+func (s *ShardedCacheSuite) TestNewCache_WithShardsOfWeightedCache_SetWithCostWasForwarded() {
+	opts := []Option{
+		WithCapacity(20),
+		WithTTL(time.Minute),
+		WithShards(2),
+	}
+
+	cache, err := NewCache[int, int](LruCacheType, opts...)
+	require.NoError(s.T(), err)
+
+	weighted, ok := cache.(WeightedCache[int, int])
+	require.True(s.T(), ok)
+
+	err = weighted.SetWithCost(1, 1, 5)
+	require.NoError(s.T(), err)
+
+	err = weighted.SetWithCost(2, 2, 11)
+	var costErr *CostTooLargeError
+	require.ErrorAs(s.T(), err, &costErr)
+	assert.Equal(s.T(), int64(10), costErr.Capacity)
+
+	ttlCache, err := NewCache[int, int](TtlCacheType, opts...)
+	require.NoError(s.T(), err)
+	_, ok = ttlCache.(WeightedCache[int, int])
+	assert.False(s.T(), ok)
+}
+
+func (s *ShardedCacheSuite) TestNewCache_WithHasher_HasherWasUsed() {
+	hasher := HasherFunc[int](func(key int) uint64 {
+		return 0
+	})
+	opts := []Option{
+		WithCapacity(10),
+		WithTTL(time.Minute),
+		WithShards(2),
+		WithHasher[int](hasher),
+	}
+
+	cache, err := NewCache[int, int](LruCacheType, opts...)
+	require.NoError(s.T(), err)
+
+	for i := range 10 {
+		err = cache.Set(i, i)
+		require.NoError(s.T(), err)
+	}
+
+	sharded := unwrapSharded(cache)
+	assert.Equal(s.T(), 5, sharded.shards[0].Len())
+	assert.Zero(s.T(), sharded.shards[1].Len())
+}
+
+func (s *ShardedCacheSuite) TestNewCache_WithHasherOfOtherType_ReturnError() {
+	hasher := HasherFunc[int](func(key int) uint64 {
+		return uint64(key)
+	})
+	opts := []Option{
+		WithCapacity(10),
+		WithTTL(time.Minute),
+		WithShards(2),
+		WithHasher[int](hasher),
+	}
+
+	cache, err := NewCache[string, int](LruCacheType, opts...)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalHasher)
+}
+
+func (s *ShardedCacheSuite) TestNewCache_WithShardsForUnsupportedKey_ReturnError() {
+	opts := []Option{
+		WithCapacity(10),
+		WithTTL(time.Minute),
+		WithShards(2),
+	}
+
+	cache, err := NewCache[struct{ id int }, int](LruCacheType, opts...)
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrHasherRequired)
+}
+
+func (s *ShardedCacheSuite) TestNewCache_IllegalShards_ReturnError() {
+	cache, err := NewCache[int, int](LruCacheType, WithCapacity(10), WithTTL(time.Minute), WithShards(-1))
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrIllegalShards)
+
+	cache, err = NewCache[int, int](LruCacheType, WithCapacity(2), WithTTL(time.Minute), WithShards(4))
+	assert.Nil(s.T(), cache)
+	assert.ErrorIs(s.T(), err, ErrTooManyShards)
+}
+
+func (s *ShardedCacheSuite) TestNewCache_WithShards_ConcurrentAccessIsSafe() {
+	cache, err := NewCache[int, int](LruCacheType, WithCapacity(100), WithTTL(time.Minute), WithShards(8))
+	require.NoError(s.T(), err)
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				_ = cache.Set(g*1000+i, i)
+				cache.Get(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(s.T(), cache.Len(), 100)
+}
+
+func unwrapSharded[K comparable, V any](cache Cache[K, V]) *shardedCache[K, V] {
+	if weighted, ok := cache.(weightedShardedCache[K, V]); ok {
+		return weighted.shardedCache
+	}
+
+	return cache.(*shardedCache[K, V])
+}
+
+func BenchmarkCache_ParallelGet(b *testing.B) {
+	for _, shards := range []int{1, 4, 16} {
+		b.Run("shards="+strconv.Itoa(shards), func(b *testing.B) {
+			cache, err := NewCache[int, int](LruCacheType, WithCapacity(10_000), WithTTL(time.Minute), WithShards(shards))
+			require.NoError(b, err)
+			for i := range 10_000 {
+				_ = cache.Set(i, i)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					cache.Get(i % 10_000)
+					i++
+				}
+			})
+		})
+	}
+}
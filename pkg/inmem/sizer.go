@@ -0,0 +1,44 @@
+package inmem
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Sizer measures the memory taken by a cache entry in bytes. It is used to
+// keep the total size of entries within the limit set by WithMaxBytes.
+type Sizer[K comparable, V any] interface {
+	Size(key K, value V) int64
+}
+
+type SizerFunc[K comparable, V any] func(key K, value V) int64
+
+func (f SizerFunc[K, V]) Size(key K, value V) int64 {
+	return f(key, value)
+}
+
+type sizeable interface {
+	Size() int
+}
+
+var sizeableType = reflect.TypeFor[sizeable]()
+
+// defaultSizer returns a sizer for values which are strings, byte slices or
+// implement Size() int. Only the value size is taken into account.
+func defaultSizer[K comparable, V any]() (func(key K, value V) int64, error) {
+	valueType := reflect.TypeFor[V]()
+
+	switch {
+	case valueType.Implements(sizeableType):
+		return func(_ K, value V) int64 {
+			return int64(any(value).(sizeable).Size())
+		}, nil
+	case valueType.Kind() == reflect.String,
+		valueType.Kind() == reflect.Slice && valueType.Elem().Kind() == reflect.Uint8:
+		return func(_ K, value V) int64 {
+			return int64(reflect.ValueOf(value).Len())
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: no default sizer for %s", ErrSizerRequired, valueType)
+	}
+}
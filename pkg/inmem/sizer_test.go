@@ -0,0 +1,60 @@
+package inmem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sizedValue struct {
+	payload []int
+}
+
+func (v sizedValue) Size() int {
+	return len(v.payload) * 8
+}
+
+type namedString string
+
+func TestDefaultSizer(t *testing.T) {
+	t.Run("String value", func(t *testing.T) {
+		sizer, err := defaultSizer[int, string]()
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), sizer(1, "value"))
+	})
+
+	t.Run("Named string value", func(t *testing.T) {
+		sizer, err := defaultSizer[int, namedString]()
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), sizer(1, namedString("value")))
+	})
+
+	t.Run("Byte slice value", func(t *testing.T) {
+		sizer, err := defaultSizer[int, []byte]()
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), sizer(1, []byte{1, 2, 3}))
+	})
+
+	t.Run("Value implementing Size", func(t *testing.T) {
+		sizer, err := defaultSizer[int, sizedValue]()
+		require.NoError(t, err)
+		assert.Equal(t, int64(16), sizer(1, sizedValue{payload: []int{1, 2}}))
+	})
+
+	t.Run("Unsupported value", func(t *testing.T) {
+		sizer, err := defaultSizer[int, int]()
+		assert.Nil(t, sizer)
+		assert.ErrorIs(t, err, ErrSizerRequired)
+	})
+}
+
+func TestSizerFunc(t *testing.T) {
+	t.Run("Function is used as sizer", func(t *testing.T) {
+		var sizer Sizer[string, int] = SizerFunc[string, int](func(key string, value int) int64 {
+			return int64(len(key) + value)
+		})
+
+		assert.Equal(t, int64(5), sizer.Size("key", 2))
+	})
+}
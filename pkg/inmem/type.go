@@ -1,9 +1,83 @@
 package inmem
 
+import (
+	"github.com/conacry/inmem-cache/internal/removal"
+	"github.com/conacry/inmem-cache/internal/stats"
+)
+
 type CacheType string
 
 const (
 	TtlCacheType CacheType = "ttl"
 	LruCacheType CacheType = "lru"
 	LfuCacheType CacheType = "lfu"
+	// ArcCacheType is an adaptive replacement cache which balances recency
+	// and frequency of use and resists scans.
+	ArcCacheType CacheType = "arc"
+	// TinyLfuCacheType is a W-TinyLFU cache which admits a new entry only
+	// if it is used more often than the entry it would replace. Keys are
+	// hashed by the hasher set with WithHasher, strings and numbers are
+	// hashed by default.
+	TinyLfuCacheType CacheType = "tinylfu"
+	// SieveCacheType is a SIEVE cache. A hit only marks the entry as
+	// visited, so concurrent hits do not contend.
+	SieveCacheType CacheType = "sieve"
+	// S3FifoCacheType is an S3-FIFO cache. It resists scans and a hit only
+	// increments a counter, so concurrent hits do not contend.
+	S3FifoCacheType CacheType = "s3fifo"
+	// ClockCacheType is a CLOCK cache, an approximation of LRU. A hit only
+	// sets a referenced bit and never moves the entry, so hits are cheaper
+	// than in an LRU cache and concurrent hits do not contend.
+	ClockCacheType CacheType = "clock"
+	// ClockProCacheType is a CLOCK-Pro cache. It keeps entries used again
+	// soon after their first use apart from entries used once, which makes
+	// it resistant to scans and loops.
+	ClockProCacheType CacheType = "clockpro"
+	// TwoQCacheType is a 2Q cache. Entries seen once stay in a small FIFO
+	// queue, and only entries seen again after leaving it get into the main
+	// LRU queue, so one-hit wonders do not evict the hot entries.
+	TwoQCacheType CacheType = "2q"
+	// SlruCacheType is a segmented LRU cache. Entries hit at least once are
+	// protected from entries seen once. The size of the protected segment
+	// is set with WithSlruProtectedRatio.
+	SlruCacheType CacheType = "slru"
+	// LruKCacheType is an LRU-K cache. It evicts the entry whose K-th last
+	// access is the oldest, so entries used once are evicted before entries
+	// used repeatedly. K is set with WithK and the number of remembered
+	// evicted keys with WithHistorySize.
+	LruKCacheType CacheType = "lruk"
+	// LirsCacheType is a LIRS cache. It keeps the entries with a low
+	// inter-reference recency and evicts the ones reused rarely, remembering
+	// keys of recently evicted entries to detect their reuse.
+	LirsCacheType CacheType = "lirs"
+	// GdsfCacheType is a GreedyDual-Size-Frequency cache. It evicts the
+	// entries with the lowest frequency per byte first, so with WithMaxBytes
+	// one large entry is evicted before many small hot ones. Entries which
+	// are not accessed anymore age out whatever their past frequency.
+	// Entries which are expensive to fetch again are kept longer if their
+	// cost is set with WithGdsfCost.
+	GdsfCacheType CacheType = "gdsf"
+)
+
+// OverflowStrategy defines how a cache with limited capacity handles a new
+// entry when it is full.
+type OverflowStrategy string
+
+const (
+	EvictOverflowStrategy  OverflowStrategy = "evict"
+	RejectOverflowStrategy OverflowStrategy = "reject"
 )
+
+// RemovalReason describes why an entry has left a cache.
+type RemovalReason = removal.Reason
+
+const (
+	CapacityRemovalReason = removal.Capacity
+	ExpiredRemovalReason  = removal.Expired
+	DeletedRemovalReason  = removal.Deleted
+	ReplacedRemovalReason = removal.Replaced
+)
+
+// Stats is a snapshot of cache counters: hits, misses, sets, removals by
+// reason and the current number of entries.
+type Stats = stats.Stats